@@ -0,0 +1,64 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:storageversion
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=clusterkeptnevaluationdefinitions,scope=Cluster,shortName=cked
+
+// ClusterKeptnEvaluationDefinition is the Schema for the clusterkeptnevaluationdefinitions API.
+// It is used by evaluations of every namespace that has no KeptnEvaluationDefinition of the same name,
+// its sources are looked up like the ones of a KeptnEvaluationDefinition in the namespace of the evaluation.
+type ClusterKeptnEvaluationDefinition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KeptnEvaluationDefinitionSpec   `json:"spec,omitempty"`
+	Status KeptnEvaluationDefinitionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterKeptnEvaluationDefinitionList contains a list of ClusterKeptnEvaluationDefinition
+type ClusterKeptnEvaluationDefinitionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterKeptnEvaluationDefinition `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterKeptnEvaluationDefinition{}, &ClusterKeptnEvaluationDefinitionList{})
+}
+
+// ToEvaluationDefinition returns the definition as KeptnEvaluationDefinition in the namespace of an evaluation
+func (d *ClusterKeptnEvaluationDefinition) ToEvaluationDefinition(namespace string) KeptnEvaluationDefinition {
+	definition := KeptnEvaluationDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       d.Name,
+			Namespace:  namespace,
+			Generation: d.Generation,
+		},
+		Spec:   d.Spec,
+		Status: d.Status,
+	}
+	return *definition.DeepCopy()
+}
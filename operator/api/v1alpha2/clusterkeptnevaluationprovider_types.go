@@ -0,0 +1,89 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterKeptnEvaluationProviderKind is the kind of ClusterKeptnEvaluationProviders,
+// it is kept by the KeptnEvaluationProviders they are converted to
+const ClusterKeptnEvaluationProviderKind = "ClusterKeptnEvaluationProvider"
+
+// ClusterKeptnEvaluationProviderSpec defines the desired state of ClusterKeptnEvaluationProvider
+type ClusterKeptnEvaluationProviderSpec struct {
+	KeptnEvaluationProviderSpec `json:",inline"`
+	// NamespaceSelector restricts the namespaces whose evaluations may use the provider,
+	// it can be used by every namespace if it is not set
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:storageversion
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=clusterkeptnevaluationproviders,scope=Cluster,shortName=ckep
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Latency",type=string,JSONPath=`.status.latency`
+
+// ClusterKeptnEvaluationProvider is the Schema for the clusterkeptnevaluationproviders API.
+// It is used by evaluations of every namespace that has no KeptnEvaluationProvider of the same name.
+// The secrets it references are read from the namespace of the lifecycle operator only,
+// so that its credentials never have to be copied to the namespaces using it.
+type ClusterKeptnEvaluationProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterKeptnEvaluationProviderSpec `json:"spec,omitempty"`
+	Status KeptnEvaluationProviderStatus      `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterKeptnEvaluationProviderList contains a list of ClusterKeptnEvaluationProvider
+type ClusterKeptnEvaluationProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterKeptnEvaluationProvider `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterKeptnEvaluationProvider{}, &ClusterKeptnEvaluationProviderList{})
+}
+
+// ToEvaluationProvider returns the provider as KeptnEvaluationProvider in the given namespace,
+// which is the namespace its secrets are read from. The kind and UID are kept, so that state cached per provider,
+// like OAuth access tokens and query limits, is shared by all evaluations using it and kept apart from the
+// namespaced provider of the same name.
+func (p *ClusterKeptnEvaluationProvider) ToEvaluationProvider(secretNamespace string) KeptnEvaluationProvider {
+	provider := KeptnEvaluationProvider{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       ClusterKeptnEvaluationProviderKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       p.Name,
+			Namespace:  secretNamespace,
+			UID:        p.UID,
+			Generation: p.Generation,
+		},
+		Spec:   p.Spec.KeptnEvaluationProviderSpec,
+		Status: p.Status,
+	}
+	return *provider.DeepCopy()
+}
@@ -21,6 +21,7 @@ const PreDeploymentEvaluationAnnotation = "keptn.sh/pre-deployment-evaluations"
 const PostDeploymentEvaluationAnnotation = "keptn.sh/post-deployment-evaluations"
 const TaskNameAnnotation = "keptn.sh/task-name"
 const NamespaceEnabledAnnotation = "keptn.sh/lifecycle-toolkit"
+const EvaluationFailActionAnnotation = "keptn.sh/evaluation-fail-action"
 const CreateAppTaskSpanName = "create_%s_app_task"
 const CreateWorkloadTaskSpanName = "create_%s_deployment_task"
 const CreateAppEvalSpanName = "create_%s_app_evaluation"
@@ -40,6 +41,10 @@ const (
 	StateUnknown     KeptnState = "Unknown"
 	StatePending     KeptnState = "Pending"
 	StateDeprecated  KeptnState = "Deprecated"
+	// StateWarning is used for evaluation objectives that met their warning target only,
+	// for evaluations whose score only reached the warning threshold
+	// and for phases that succeeded although some of their evaluations failed with fail action warn
+	StateWarning KeptnState = "Warning"
 )
 
 func (k KeptnState) IsCompleted() bool {
@@ -62,6 +67,10 @@ func (k KeptnState) IsPending() bool {
 	return k == StatePending
 }
 
+func (k KeptnState) IsWarning() bool {
+	return k == StateWarning
+}
+
 type StatusSummary struct {
 	Total       int
 	Progressing int
@@ -70,6 +79,9 @@ type StatusSummary struct {
 	Pending     int
 	Unknown     int
 	Deprecated  int
+	// Warning counts the succeeded items that only succeeded with a warning,
+	// either because of their state or because their failure was tolerated with a warning
+	Warning int
 }
 
 func UpdateStatusSummary(status KeptnState, summary StatusSummary) StatusSummary {
@@ -80,6 +92,9 @@ func UpdateStatusSummary(status KeptnState, summary StatusSummary) StatusSummary
 		summary.Deprecated++
 	case StateSucceeded:
 		summary.Succeeded++
+	case StateWarning:
+		summary.Succeeded++
+		summary.Warning++
 	case StateProgressing:
 		summary.Progressing++
 	case StatePending, "":
@@ -110,6 +125,16 @@ func GetOverallState(s StatusSummary) KeptnState {
 	return StateSucceeded
 }
 
+// GetOverallStateWithWarnings returns the overall state, but reports StateWarning instead of StateSucceeded
+// if some of the items only succeeded with a warning
+func GetOverallStateWithWarnings(s StatusSummary) KeptnState {
+	state := GetOverallState(s)
+	if state.IsSucceeded() && s.Warning > 0 {
+		return StateWarning
+	}
+	return state
+}
+
 func TruncateString(s string, max int) string {
 	if len(s) > max {
 		return s[:max]
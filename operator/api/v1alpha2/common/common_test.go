@@ -120,39 +120,64 @@ func TestKeptnKeptnState_IsPending(t *testing.T) {
 	}
 }
 
+func TestKeptnKeptnState_IsWarning(t *testing.T) {
+	tests := []struct {
+		State KeptnState
+		Want  bool
+	}{
+		{
+			State: StateSucceeded,
+			Want:  false,
+		},
+		{
+			State: StateWarning,
+			Want:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			require.Equal(t, tt.State.IsWarning(), tt.Want)
+		})
+	}
+}
+
 func Test_UpdateStatusSummary(t *testing.T) {
-	emmptySummary := StatusSummary{0, 0, 0, 0, 0, 0, 0}
+	emmptySummary := StatusSummary{0, 0, 0, 0, 0, 0, 0, 0}
 	tests := []struct {
 		State KeptnState
 		Want  StatusSummary
 	}{
 		{
 			State: StateProgressing,
-			Want:  StatusSummary{0, 1, 0, 0, 0, 0, 0},
+			Want:  StatusSummary{0, 1, 0, 0, 0, 0, 0, 0},
 		},
 		{
 			State: StateFailed,
-			Want:  StatusSummary{0, 0, 1, 0, 0, 0, 0},
+			Want:  StatusSummary{0, 0, 1, 0, 0, 0, 0, 0},
 		},
 		{
 			State: StateSucceeded,
-			Want:  StatusSummary{0, 0, 0, 1, 0, 0, 0},
+			Want:  StatusSummary{0, 0, 0, 1, 0, 0, 0, 0},
+		},
+		{
+			State: StateWarning,
+			Want:  StatusSummary{0, 0, 0, 1, 0, 0, 0, 1},
 		},
 		{
 			State: StatePending,
-			Want:  StatusSummary{0, 0, 0, 0, 1, 0, 0},
+			Want:  StatusSummary{0, 0, 0, 0, 1, 0, 0, 0},
 		},
 		{
 			State: "",
-			Want:  StatusSummary{0, 0, 0, 0, 1, 0, 0},
+			Want:  StatusSummary{0, 0, 0, 0, 1, 0, 0, 0},
 		},
 		{
 			State: StateUnknown,
-			Want:  StatusSummary{0, 0, 0, 0, 0, 1, 0},
+			Want:  StatusSummary{0, 0, 0, 0, 0, 1, 0, 0},
 		},
 		{
 			State: StateDeprecated,
-			Want:  StatusSummary{0, 0, 0, 0, 0, 0, 1},
+			Want:  StatusSummary{0, 0, 0, 0, 0, 0, 1, 0},
 		},
 	}
 	for _, tt := range tests {
@@ -163,7 +188,7 @@ func Test_UpdateStatusSummary(t *testing.T) {
 }
 
 func Test_GetTotalCount(t *testing.T) {
-	summary := StatusSummary{2, 0, 2, 1, 0, 3, 5}
+	summary := StatusSummary{2, 0, 2, 1, 0, 3, 5, 0}
 	require.Equal(t, summary.GetTotalCount(), 11)
 }
 
@@ -175,37 +200,37 @@ func Test_GeOverallState(t *testing.T) {
 	}{
 		{
 			Name:    "failed",
-			Summary: StatusSummary{0, 0, 1, 0, 0, 0, 0},
+			Summary: StatusSummary{0, 0, 1, 0, 0, 0, 0, 0},
 			Want:    StateFailed,
 		},
 		{
 			Name:    "Deprecated",
-			Summary: StatusSummary{0, 0, 0, 0, 0, 0, 1},
+			Summary: StatusSummary{0, 0, 0, 0, 0, 0, 1, 0},
 			Want:    StateFailed,
 		},
 		{
 			Name:    "progressing",
-			Summary: StatusSummary{0, 1, 0, 0, 0, 0, 0},
+			Summary: StatusSummary{0, 1, 0, 0, 0, 0, 0, 0},
 			Want:    StateProgressing,
 		},
 		{
 			Name:    "pending",
-			Summary: StatusSummary{0, 0, 0, 0, 1, 0, 0},
+			Summary: StatusSummary{0, 0, 0, 0, 1, 0, 0, 0},
 			Want:    StatePending,
 		},
 		{
 			Name:    "unknown",
-			Summary: StatusSummary{0, 0, 0, 0, 0, 1, 0},
+			Summary: StatusSummary{0, 0, 0, 0, 0, 1, 0, 0},
 			Want:    StateUnknown,
 		},
 		{
 			Name:    "unknown totalcount",
-			Summary: StatusSummary{5, 0, 0, 0, 0, 1, 0},
+			Summary: StatusSummary{5, 0, 0, 0, 0, 1, 0, 0},
 			Want:    StateUnknown,
 		},
 		{
 			Name:    "succeeded",
-			Summary: StatusSummary{1, 0, 0, 1, 0, 0, 0},
+			Summary: StatusSummary{1, 0, 0, 1, 0, 0, 0, 0},
 			Want:    StateSucceeded,
 		},
 	}
@@ -216,6 +241,40 @@ func Test_GeOverallState(t *testing.T) {
 	}
 }
 
+func Test_GetOverallStateWithWarnings(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Summary StatusSummary
+		Want    KeptnState
+	}{
+		{
+			Name:    "succeeded",
+			Summary: StatusSummary{2, 0, 0, 2, 0, 0, 0, 0},
+			Want:    StateSucceeded,
+		},
+		{
+			Name:    "succeeded with warning",
+			Summary: StatusSummary{2, 0, 0, 2, 0, 0, 0, 1},
+			Want:    StateWarning,
+		},
+		{
+			Name:    "progressing with warning",
+			Summary: StatusSummary{2, 1, 0, 1, 0, 0, 0, 1},
+			Want:    StateProgressing,
+		},
+		{
+			Name:    "failed with warning",
+			Summary: StatusSummary{2, 0, 1, 1, 0, 0, 0, 1},
+			Want:    StateFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			require.Equal(t, tt.Want, GetOverallStateWithWarnings(tt.Summary))
+		})
+	}
+}
+
 func Test_TruncateString(t *testing.T) {
 	tests := []struct {
 		Input string
@@ -192,6 +192,7 @@ func TestKeptnAppVersion(t *testing.T) {
 	require.Equal(t, KeptnEvaluationSpec{
 		AppVersion:           app.GetVersion(),
 		AppName:              app.GetParentName(),
+		PreviousVersion:      app.GetPreviousVersion(),
 		EvaluationDefinition: "taskdef",
 		Type:                 common.PostDeploymentCheckType,
 		RetryInterval: metav1.Duration{
@@ -326,6 +326,7 @@ func (a KeptnAppVersion) GenerateEvaluation(evaluationDefinition string, checkTy
 		Spec: KeptnEvaluationSpec{
 			AppVersion:           a.Spec.Version,
 			AppName:              a.Spec.AppName,
+			PreviousVersion:      a.Spec.PreviousVersion,
 			EvaluationDefinition: evaluationDefinition,
 			Type:                 checkType,
 			RetryInterval: metav1.Duration{
@@ -1,7 +1,9 @@
 package v1alpha2
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2/common"
 	"github.com/stretchr/testify/require"
@@ -105,3 +107,26 @@ func TestKeptnEvaluationList(t *testing.T) {
 	got := list.GetItems()
 	require.Len(t, got, 2)
 }
+
+func TestEvaluationStatusItem_RecordAttempt(t *testing.T) {
+	start := time.Date(2022, 12, 1, 10, 0, 0, 0, time.UTC)
+	var history []EvaluationAttempt
+	for i := 0; i < MaxEvaluationAttempts+2; i++ {
+		item := EvaluationStatusItem{
+			Value:   fmt.Sprintf("%d", i),
+			Status:  common.StateFailed,
+			Message: "failed",
+		}
+		item.RecordAttempt(history, start.Add(time.Duration(i)*time.Minute))
+		history = item.History
+	}
+
+	require.Len(t, history, MaxEvaluationAttempts)
+	require.Equal(t, "2", history[0].Value)
+	require.Equal(t, EvaluationAttempt{
+		Time:    metav1.NewTime(start.Add(time.Duration(MaxEvaluationAttempts+1) * time.Minute)),
+		Value:   fmt.Sprintf("%d", MaxEvaluationAttempts+1),
+		Status:  common.StateFailed,
+		Message: "failed",
+	}, history[MaxEvaluationAttempts-1])
+}
@@ -32,10 +32,12 @@ import (
 
 // KeptnEvaluationSpec defines the desired state of KeptnEvaluation
 type KeptnEvaluationSpec struct {
-	Workload             string `json:"workload,omitempty"`
-	WorkloadVersion      string `json:"workloadVersion"`
-	AppName              string `json:"appName,omitempty"`
-	AppVersion           string `json:"appVersion,omitempty"`
+	Workload        string `json:"workload,omitempty"`
+	WorkloadVersion string `json:"workloadVersion"`
+	AppName         string `json:"appName,omitempty"`
+	AppVersion      string `json:"appVersion,omitempty"`
+	// PreviousVersion is the version of the workload or app that was deployed before, used by relative objectives
+	PreviousVersion      string `json:"previousVersion,omitempty"`
 	EvaluationDefinition string `json:"evaluationDefinition"`
 	// +kubebuilder:default:=10
 	Retries int `json:"retries,omitempty"`
@@ -44,9 +46,33 @@ type KeptnEvaluationSpec struct {
 	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
 	// +kubebuilder:validation:Type:=string
 	// +optional
-	RetryInterval metav1.Duration  `json:"retryInterval,omitempty"`
-	FailAction    string           `json:"failAction,omitempty"`
-	Type          common.CheckType `json:"checkType,omitempty"`
+	RetryInterval metav1.Duration `json:"retryInterval,omitempty"`
+	// FailAction defines how a failed evaluation affects the phase it belongs to, defaults to fail
+	// +optional
+	FailAction FailAction       `json:"failAction,omitempty"`
+	Type       common.CheckType `json:"checkType,omitempty"`
+}
+
+// FailAction defines how a failed evaluation affects the phase it belongs to
+// +kubebuilder:validation:Enum=fail;warn;ignore
+type FailAction string
+
+const (
+	// FailActionFail fails the phase and deprecates the remaining phases
+	FailActionFail FailAction = "fail"
+	// FailActionWarn lets the phase succeed, but records a warning
+	FailActionWarn FailAction = "warn"
+	// FailActionIgnore lets the phase succeed as if the evaluation had passed
+	FailActionIgnore FailAction = "ignore"
+)
+
+// IsValid returns true if the fail action is empty or one of the known fail actions
+func (f FailAction) IsValid() bool {
+	switch f {
+	case "", FailActionFail, FailActionWarn, FailActionIgnore:
+		return true
+	}
+	return false
 }
 
 // KeptnEvaluationStatus defines the observed state of KeptnEvaluation
@@ -54,16 +80,64 @@ type KeptnEvaluationStatus struct {
 	// +kubebuilder:default:=0
 	RetryCount       int                             `json:"retryCount"`
 	EvaluationStatus map[string]EvaluationStatusItem `json:"evaluationStatus"`
+	// OverallStatus is Warning if the score of the evaluation only reached the warning threshold
 	// +kubebuilder:default:=Pending
 	OverallStatus common.KeptnState `json:"overallStatus"`
 	StartTime     metav1.Time       `json:"startTime,omitempty"`
 	EndTime       metav1.Time       `json:"endTime,omitempty"`
+	// Score is the total score of the evaluation in percent, if scoring is enabled in the KeptnEvaluationDefinition
+	// +optional
+	Score string `json:"score,omitempty"`
 }
 
 type EvaluationStatusItem struct {
 	Value   string            `json:"value"`
 	Status  common.KeptnState `json:"status"`
 	Message string            `json:"message,omitempty"`
+	// Score is the contribution of the objective to the total score of the evaluation
+	// +optional
+	Score string `json:"score,omitempty"`
+	// PreviousValue is the value of the previous version, if the objective has a comparison
+	// +optional
+	PreviousValue string `json:"previousValue,omitempty"`
+	// Change is the change compared to the previous version that has been checked against the targets
+	// +optional
+	Change string `json:"change,omitempty"`
+	// Baseline is the center of the baseline of anomaly detection, i.e. the mean or the median of its values
+	// +optional
+	Baseline string `json:"baseline,omitempty"`
+	// Deviation is the distance of the value from the baseline in standard deviations, if the objective detects anomalies
+	// +optional
+	Deviation string `json:"deviation,omitempty"`
+	// History contains the last attempts to evaluate the objective, oldest first
+	// +optional
+	History []EvaluationAttempt `json:"history,omitempty"`
+	// Series contains every series of a query result with more than one series
+	// +optional
+	Series []SeriesStatus `json:"series,omitempty"`
+}
+
+// SeriesStatus is the result of a single series of a query
+type SeriesStatus struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  string            `json:"value"`
+	// Status is the result of checking the value of the series against the targets of the objective,
+	// it is not set if the targets apply to the change compared to the previous version
+	// +optional
+	Status common.KeptnState `json:"status,omitempty"`
+}
+
+// MaxEvaluationAttempts is the number of attempts kept in the history of an objective
+const MaxEvaluationAttempts = 10
+
+// EvaluationAttempt is the result of a single attempt to evaluate an objective
+type EvaluationAttempt struct {
+	Time   metav1.Time       `json:"time"`
+	Value  string            `json:"value,omitempty"`
+	Status common.KeptnState `json:"status"`
+	// Message contains the error or the reason why the attempt did not pass
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -77,6 +151,7 @@ type EvaluationStatusItem struct {
 //+kubebuilder:printcolumn:name="RetryCount",type=string,JSONPath=`.status.retryCount`
 //+kubebuilder:printcolumn:name="EvaluationStatus",type=string,JSONPath=`.status.evaluationStatus`
 //+kubebuilder:printcolumn:name="OverallStatus",type=string,JSONPath=`.status.overallStatus`
+//+kubebuilder:printcolumn:name="Score",type=string,JSONPath=`.status.score`
 
 // KeptnEvaluation is the Schema for the keptnevaluations API
 type KeptnEvaluation struct {
@@ -120,6 +195,19 @@ func (e *KeptnEvaluation) SetEndTime() {
 	}
 }
 
+// GetFailAction returns the fail action of the evaluation, defaulting to fail
+func (e KeptnEvaluation) GetFailAction() FailAction {
+	if e.Spec.FailAction == "" {
+		return FailActionFail
+	}
+	return e.Spec.FailAction
+}
+
+// IsPassed checks if the evaluation succeeded, with or without a warning
+func (e KeptnEvaluation) IsPassed() bool {
+	return e.Status.OverallStatus.IsSucceeded() || e.Status.OverallStatus.IsWarning()
+}
+
 func (e *KeptnEvaluation) IsStartTimeSet() bool {
 	return !e.Status.StartTime.IsZero()
 }
@@ -163,6 +251,23 @@ func (e *KeptnEvaluation) AddEvaluationStatus(objective Objective) {
 
 }
 
+// RecordAttempt adds the current result of the item to the given history of attempts,
+// keeping only the last MaxEvaluationAttempts attempts
+func (i *EvaluationStatusItem) RecordAttempt(history []EvaluationAttempt, attemptTime time.Time) {
+	attempts := make([]EvaluationAttempt, 0, len(history)+1)
+	attempts = append(attempts, history...)
+	attempts = append(attempts, EvaluationAttempt{
+		Time:    metav1.NewTime(attemptTime.UTC()),
+		Value:   i.Value,
+		Status:  i.Status,
+		Message: i.Message,
+	})
+	if len(attempts) > MaxEvaluationAttempts {
+		attempts = attempts[len(attempts)-MaxEvaluationAttempts:]
+	}
+	i.History = attempts
+}
+
 func (e KeptnEvaluation) SetSpanAttributes(span trace.Span) {
 	span.SetAttributes(e.GetSpanAttributes()...)
 }
@@ -189,3 +294,11 @@ func (e KeptnEvaluation) GetSpanKey(phase string) string {
 func (e KeptnEvaluation) GetSpanName(phase string) string {
 	return e.Name
 }
+
+// GetVersion returns the workload version for workload evaluations and the app version for app evaluations
+func (e KeptnEvaluation) GetVersion() string {
+	if e.Spec.Workload != "" {
+		return e.Spec.WorkloadVersion
+	}
+	return e.Spec.AppVersion
+}
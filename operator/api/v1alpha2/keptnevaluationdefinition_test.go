@@ -0,0 +1,21 @@
+package v1alpha2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestKeptnEvaluationDefinition_GetEarliestQueryTime(t *testing.T) {
+	start := time.Date(2022, 12, 1, 10, 0, 0, 0, time.UTC)
+	definition := KeptnEvaluationDefinition{}
+	require.Equal(t, start, definition.GetObservationStart(start))
+	require.Equal(t, start, definition.GetEarliestQueryTime(start))
+
+	definition.Spec.InitialDelay = metav1.Duration{Duration: 5 * time.Minute}
+	definition.Spec.ObservationWindow = metav1.Duration{Duration: 10 * time.Minute}
+	require.Equal(t, start.Add(5*time.Minute), definition.GetObservationStart(start))
+	require.Equal(t, start.Add(15*time.Minute), definition.GetEarliestQueryTime(start))
+}
@@ -17,6 +17,10 @@ limitations under the License.
 package v1alpha2
 
 import (
+	"fmt"
+	"strconv"
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,14 +29,385 @@ import (
 
 // KeptnEvaluationDefinitionSpec defines the desired state of KeptnEvaluationDefinition
 type KeptnEvaluationDefinitionSpec struct {
-	Source     string      `json:"source"`
+	// Source is the name of the KeptnEvaluationProvider querying the objectives without their own source,
+	// it is required if any objective does not set a source
+	// +optional
+	Source     string      `json:"source,omitempty"`
 	Objectives []Objective `json:"objectives"`
+	// TotalScore enables weighted scoring of the objectives.
+	// If it is not set, every objective has to pass for the evaluation to succeed.
+	// +optional
+	TotalScore *TotalScore `json:"totalScore,omitempty"`
+	// FailAction defines how a failed evaluation affects the phase it belongs to, defaults to fail.
+	// It can be overridden per workload with the keptn.sh/evaluation-fail-action annotation.
+	// +optional
+	FailAction FailAction `json:"failAction,omitempty"`
+	// InitialDelay is the time to wait after the start of the evaluation before the objectives are queried,
+	// e.g. to give a new version time to receive traffic after its deployment
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	InitialDelay metav1.Duration `json:"initialDelay,omitempty"`
+	// ObservationWindow is the minimum time that is observed after the initial delay before the objectives are queried.
+	// Query ranges starting at the start of the phase start at the end of the initial delay instead.
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	ObservationWindow metav1.Duration `json:"observationWindow,omitempty"`
+	// Retry defines how the interval between the attempts of an evaluation grows.
+	// If it is not set, the retry interval of the evaluation is used for every attempt.
+	// +optional
+	Retry *RetryStrategy `json:"retry,omitempty"`
+}
+
+type RetryStrategy struct {
+	// Backoff defines how the retry interval of the evaluation grows with every attempt
+	// +kubebuilder:validation:Enum:=fixed;linear;exponential
+	// +kubebuilder:default:=fixed
+	// +optional
+	Backoff BackoffStrategy `json:"backoff,omitempty"`
+	// MaxInterval caps the interval between two attempts
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	MaxInterval metav1.Duration `json:"maxInterval,omitempty"`
+	// Jitter randomly changes the interval by up to the given percentage
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=100
+	// +optional
+	Jitter int `json:"jitter,omitempty"`
 }
 
+type BackoffStrategy string
+
+const (
+	// BackoffFixed waits the retry interval between all attempts
+	BackoffFixed BackoffStrategy = "fixed"
+	// BackoffLinear waits the retry interval multiplied by the number of attempts
+	BackoffLinear BackoffStrategy = "linear"
+	// BackoffExponential doubles the interval with every attempt
+	BackoffExponential BackoffStrategy = "exponential"
+)
+
 type Objective struct {
 	Name             string `json:"name"`
 	Query            string `json:"query"`
 	EvaluationTarget string `json:"evaluationTarget"`
+	// Source is the name of the KeptnEvaluationProvider querying the objective,
+	// it overrides the source of the definition
+	// +optional
+	Source string `json:"source,omitempty"`
+	// Timeout of the query, overrides the query timeout of the provider
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// WarningTarget is checked if the EvaluationTarget is not met.
+	// An objective meeting only its WarningTarget contributes half of its weight to the total score.
+	// +optional
+	WarningTarget string `json:"warningTarget,omitempty"`
+	// Weight of the objective in the total score
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	Weight int `json:"weight,omitempty"`
+	// KeySLI marks an objective that has to pass for the evaluation to succeed, regardless of the total score
+	// +optional
+	KeySLI bool `json:"keySLI,omitempty"`
+	// Comparison turns the objective into a relative one: the targets are checked against the change
+	// of the result compared to the previous version instead of the result itself
+	// +optional
+	Comparison *ObjectiveComparison `json:"comparison,omitempty"`
+	// Anomaly compares the result with a baseline computed from the values of the query in a lookback window
+	// before the start of the evaluation. The objective fails if the result deviates from the baseline by more
+	// than the allowed deviation, in addition to the targets. It is only supported by the prometheus provider.
+	// +optional
+	Anomaly *AnomalyDetection `json:"anomaly,omitempty"`
+	// HTTP configures the request sent by the http provider, the query is used as path relative to the target server
+	// +optional
+	HTTP *HTTPRequest `json:"http,omitempty"`
+	// Range defines the time window of providers querying metrics over a period of time.
+	// If it is set, the prometheus provider runs a range query instead of an instant query.
+	// +optional
+	Range *QueryRange `json:"range,omitempty"`
+	// Kubernetes configures the resources read by the kubernetes provider,
+	// the query is the type of the query: count, field or metrics
+	// +optional
+	Kubernetes *KubernetesQuery `json:"kubernetes,omitempty"`
+	// Dynatrace configures the metrics query of the dynatrace provider, the query is the metric selector
+	// +optional
+	Dynatrace *DynatraceQuery `json:"dynatrace,omitempty"`
+	// Static configures the ConfigMap read by the static provider. If it is not set, the query itself is the value.
+	// +optional
+	Static *StaticQuery `json:"static,omitempty"`
+	// Alertmanager configures which alerts are counted by the alertmanager provider, the query holds the label matchers
+	// +optional
+	Alertmanager *AlertmanagerQuery `json:"alertmanager,omitempty"`
+	// MultiSeries defines how query results with more than one series are handled by the prometheus provider.
+	// They are either reduced to a single value (sum, avg, max or min), or every series is checked against
+	// the targets and all or any of them have to pass. If it is not set, such results are an error.
+	// +kubebuilder:validation:Enum:=sum;avg;max;min;all;any
+	// +optional
+	MultiSeries MultiSeriesMode `json:"multiSeries,omitempty"`
+}
+
+type MultiSeriesMode string
+
+const (
+	MultiSeriesSum MultiSeriesMode = "sum"
+	MultiSeriesAvg MultiSeriesMode = "avg"
+	MultiSeriesMax MultiSeriesMode = "max"
+	MultiSeriesMin MultiSeriesMode = "min"
+	// MultiSeriesAll requires every series to meet the targets
+	MultiSeriesAll MultiSeriesMode = "all"
+	// MultiSeriesAny requires at least one series to meet the targets
+	MultiSeriesAny MultiSeriesMode = "any"
+)
+
+// IsPerSeries returns true if every series is checked against the targets instead of a single value
+func (m MultiSeriesMode) IsPerSeries() bool {
+	return m == MultiSeriesAll || m == MultiSeriesAny
+}
+
+type KubernetesQueryType string
+
+const (
+	// KubernetesQueryCount counts the resources matching the selectors and the JSONPath filter
+	KubernetesQueryCount KubernetesQueryType = "count"
+	// KubernetesQueryField reads a numeric field of a single resource
+	KubernetesQueryField KubernetesQueryType = "field"
+	// KubernetesQueryMetrics sums up the resource usage reported by the metrics.k8s.io API
+	KubernetesQueryMetrics KubernetesQueryType = "metrics"
+)
+
+type KubernetesQuery struct {
+	// APIVersion of the resources, e.g. v1 or apps/v1. It is ignored by metrics queries.
+	// +kubebuilder:default:=v1
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+	// Kind of the resources, e.g. Pod or Deployment. Metrics queries support Pod and Node. Secrets cannot be read.
+	Kind string `json:"kind"`
+	// Namespace of the resources. Resources are only read from the namespace of the evaluation,
+	// so it can only repeat that namespace. It is ignored for cluster scoped resources.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the resource, required by field queries
+	// +optional
+	Name string `json:"name,omitempty"`
+	// LabelSelector restricts the resources to the ones matching the labels, e.g. app=podtato-head
+	// +optional
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// FieldSelector restricts the resources to the ones matching the fields, e.g. status.phase!=Running
+	// +optional
+	FieldSelector string `json:"fieldSelector,omitempty"`
+	// JSONPath selects the value of field queries. For count queries, only resources
+	// for which the expression selects a value are counted.
+	// +optional
+	JSONPath string `json:"jsonPath,omitempty"`
+	// Resource read by metrics queries
+	// +kubebuilder:validation:Enum:=cpu;memory
+	// +kubebuilder:default:=cpu
+	// +optional
+	Resource string `json:"resource,omitempty"`
+	// Utilization returns the usage of metrics queries of nodes in percent of their allocatable resources
+	// +optional
+	Utilization bool `json:"utilization,omitempty"`
+}
+
+type StaticQuery struct {
+	// ConfigMap holding the value, in the namespace of the evaluation
+	ConfigMap string `json:"configMap"`
+	// Key of the value in the ConfigMap, defaults to the query
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+type AlertmanagerQuery struct {
+	// Receiver only counts the alerts sent to receivers matching the regular expression
+	// +optional
+	Receiver string `json:"receiver,omitempty"`
+	// IncludeSilenced counts silenced alerts as well
+	// +optional
+	IncludeSilenced bool `json:"includeSilenced,omitempty"`
+	// IncludeInhibited counts inhibited alerts as well
+	// +optional
+	IncludeInhibited bool `json:"includeInhibited,omitempty"`
+}
+
+type DynatraceQuery struct {
+	// Aggregation reduces the data points of all series to the single value that is checked against the targets
+	// +kubebuilder:validation:Enum:=avg;max;min;sum;last;percentile
+	// +kubebuilder:default:=avg
+	// +optional
+	Aggregation DynatraceAggregation `json:"aggregation,omitempty"`
+	// Percentile of the data points returned by the percentile aggregation
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=100
+	// +kubebuilder:default:=95
+	// +optional
+	Percentile int `json:"percentile,omitempty"`
+	// Resolution of the data points, e.g. 1m, 1h or Inf for a single data point
+	// +optional
+	Resolution string `json:"resolution,omitempty"`
+	// From is the start of the timeframe relative to the start of the evaluation, e.g. -10m.
+	// If it is not set, the timeframe starts the interval of the range before the query,
+	// or the default timeframe of Dynatrace is used if the objective has no range.
+	// +kubebuilder:validation:Pattern="^-?(0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$"
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	From *metav1.Duration `json:"from,omitempty"`
+	// To is the end of the timeframe relative to the start of the evaluation, defaults to the time of the query
+	// +kubebuilder:validation:Pattern="^-?(0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$"
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	To *metav1.Duration `json:"to,omitempty"`
+	// EntitySelector restricts the query to the matching entities, e.g. type("SERVICE"),tag("app:podtato-head")
+	// +optional
+	EntitySelector string `json:"entitySelector,omitempty"`
+}
+
+type DynatraceAggregation string
+
+const (
+	DynatraceAggregationAvg        DynatraceAggregation = "avg"
+	DynatraceAggregationMax        DynatraceAggregation = "max"
+	DynatraceAggregationMin        DynatraceAggregation = "min"
+	DynatraceAggregationSum        DynatraceAggregation = "sum"
+	DynatraceAggregationLast       DynatraceAggregation = "last"
+	DynatraceAggregationPercentile DynatraceAggregation = "percentile"
+)
+
+type QueryRange struct {
+	// Interval is the length of the time window ending at the time of the query
+	// +kubebuilder:default:="5m"
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// SincePhaseStart starts the time window at the start of the evaluation instead of using the interval
+	// +optional
+	SincePhaseStart bool `json:"sincePhaseStart,omitempty"`
+	// Step is the resolution of range queries
+	// +kubebuilder:default:="1m"
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	Step metav1.Duration `json:"step,omitempty"`
+	// Aggregation reduces the values of the time window to the single value that is checked against the targets
+	// +kubebuilder:validation:Enum:=avg;max;min;p90;p95;p99;last
+	// +kubebuilder:default:=avg
+	// +optional
+	Aggregation RangeAggregation `json:"aggregation,omitempty"`
+}
+
+type RangeAggregation string
+
+const (
+	RangeAggregationAvg  RangeAggregation = "avg"
+	RangeAggregationMax  RangeAggregation = "max"
+	RangeAggregationMin  RangeAggregation = "min"
+	RangeAggregationP90  RangeAggregation = "p90"
+	RangeAggregationP95  RangeAggregation = "p95"
+	RangeAggregationP99  RangeAggregation = "p99"
+	RangeAggregationLast RangeAggregation = "last"
+)
+
+type HTTPRequest struct {
+	// Method of the request
+	// +kubebuilder:default:=GET
+	// +optional
+	Method string `json:"method,omitempty"`
+	// Headers are added to the request
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body of the request
+	// +optional
+	Body string `json:"body,omitempty"`
+	// JSONPath expression extracting the numeric value from the JSON response, e.g. {.data.value}
+	JSONPath string `json:"jsonPath"`
+}
+
+type ComparisonBaseline string
+
+const (
+	// ComparisonBaselineQuery runs the query scoped to the previous version
+	ComparisonBaselineQuery ComparisonBaseline = "query"
+	// ComparisonBaselineEvaluation uses the value stored by the last succeeded evaluation of the previous version
+	ComparisonBaselineEvaluation ComparisonBaseline = "evaluation"
+)
+
+type ComparisonType string
+
+const (
+	// ComparisonTypeRelative compares the change in percent
+	ComparisonTypeRelative ComparisonType = "relative"
+	// ComparisonTypeAbsolute compares the difference of the values
+	ComparisonTypeAbsolute ComparisonType = "absolute"
+)
+
+type ObjectiveComparison struct {
+	// Baseline defines how the value of the previous version is retrieved
+	// +kubebuilder:validation:Enum:=query;evaluation
+	// +kubebuilder:default:=query
+	// +optional
+	Baseline ComparisonBaseline `json:"baseline,omitempty"`
+	// Type defines whether the targets are checked against the change in percent or the difference of the values
+	// +kubebuilder:validation:Enum:=relative;absolute
+	// +kubebuilder:default:=relative
+	// +optional
+	Type ComparisonType `json:"type,omitempty"`
+	// PreviousVersionQuery is the query used for the query baseline.
+	// If it is not set, the query of the objective is rendered for the previous version,
+	// which requires it to use the {{.WorkloadVersion}} or {{.AppVersion}} variable.
+	// +optional
+	PreviousVersionQuery string `json:"previousVersionQuery,omitempty"`
+}
+
+type AnomalyMethod string
+
+const (
+	// AnomalyMethodStdDev uses the mean of the baseline and its standard deviation
+	AnomalyMethodStdDev AnomalyMethod = "stddev"
+	// AnomalyMethodMAD uses the median of the baseline and its median absolute deviation,
+	// scaled to be comparable to the standard deviation, which makes it robust against outliers
+	AnomalyMethodMAD AnomalyMethod = "mad"
+)
+
+type AnomalyDetection struct {
+	// Lookback is the length of the time window before the start of the evaluation the baseline is computed from
+	// +kubebuilder:default:="1h"
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	Lookback metav1.Duration `json:"lookback,omitempty"`
+	// Step is the resolution of the range query of the baseline
+	// +kubebuilder:default:="1m"
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	Step metav1.Duration `json:"step,omitempty"`
+	// Method defines how the center and the spread of the baseline are computed
+	// +kubebuilder:validation:Enum:=stddev;mad
+	// +kubebuilder:default:=stddev
+	// +optional
+	Method AnomalyMethod `json:"method,omitempty"`
+	// MaxDeviation is the number of standard deviations the result may deviate from the center of the baseline
+	// +kubebuilder:validation:Pattern="^[0-9]+(\\.[0-9]+)?$"
+	// +kubebuilder:default:="3"
+	// +optional
+	MaxDeviation string `json:"maxDeviation,omitempty"`
+}
+
+type TotalScore struct {
+	// PassPercentage is the minimum score in percent for the evaluation to succeed
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=100
+	PassPercentage int `json:"passPercentage"`
+	// WarningPercentage is the minimum score in percent for the evaluation to succeed with a warning
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=100
+	// +optional
+	WarningPercentage int `json:"warningPercentage,omitempty"`
 }
 
 // KeptnEvaluationDefinitionStatus defines the observed state of KeptnEvaluationDefinition
@@ -67,3 +442,148 @@ type KeptnEvaluationDefinitionList struct {
 func init() {
 	SchemeBuilder.Register(&KeptnEvaluationDefinition{}, &KeptnEvaluationDefinitionList{})
 }
+
+func (o Objective) GetWeight() int {
+	if o.Weight < 1 {
+		return 1
+	}
+	return o.Weight
+}
+
+// GetTimeout returns the timeout of the objective, falling back to the query timeout of the provider.
+// A zero timeout leaves the default timeout of the provider implementation in place.
+func (o Objective) GetTimeout(provider KeptnEvaluationProvider) time.Duration {
+	if o.Timeout != nil && o.Timeout.Duration > 0 {
+		return o.Timeout.Duration
+	}
+	return provider.Spec.QueryTimeout.Duration
+}
+
+// GetRangeInterval returns the time window of the objective, defaulting to five minutes
+func (o Objective) GetRangeInterval() time.Duration {
+	if o.Range == nil || o.Range.Interval.Duration <= 0 {
+		return 5 * time.Minute
+	}
+	return o.Range.Interval.Duration
+}
+
+// GetRangeStep returns the resolution of range queries of the objective, defaulting to one minute
+func (o Objective) GetRangeStep() time.Duration {
+	if o.Range == nil || o.Range.Step.Duration <= 0 {
+		return time.Minute
+	}
+	return o.Range.Step.Duration
+}
+
+// GetRangeAggregation returns the aggregation of range queries of the objective, defaulting to avg
+func (o Objective) GetRangeAggregation() RangeAggregation {
+	if o.Range == nil || o.Range.Aggregation == "" {
+		return RangeAggregationAvg
+	}
+	return o.Range.Aggregation
+}
+
+// GetAggregation returns the aggregation of the data points, defaulting to avg
+func (q DynatraceQuery) GetAggregation() DynatraceAggregation {
+	if q.Aggregation == "" {
+		return DynatraceAggregationAvg
+	}
+	return q.Aggregation
+}
+
+// GetPercentile returns the percentile of the percentile aggregation, defaulting to 95
+func (q DynatraceQuery) GetPercentile() int {
+	if q.Percentile <= 0 {
+		return 95
+	}
+	return q.Percentile
+}
+
+func (c ObjectiveComparison) GetBaseline() ComparisonBaseline {
+	if c.Baseline == "" {
+		return ComparisonBaselineQuery
+	}
+	return c.Baseline
+}
+
+func (c ObjectiveComparison) GetType() ComparisonType {
+	if c.Type == "" {
+		return ComparisonTypeRelative
+	}
+	return c.Type
+}
+
+// GetLookback returns the time window of the baseline, defaulting to one hour
+func (a AnomalyDetection) GetLookback() time.Duration {
+	if a.Lookback.Duration <= 0 {
+		return time.Hour
+	}
+	return a.Lookback.Duration
+}
+
+// GetStep returns the resolution of the range query of the baseline, defaulting to one minute
+func (a AnomalyDetection) GetStep() time.Duration {
+	if a.Step.Duration <= 0 {
+		return time.Minute
+	}
+	return a.Step.Duration
+}
+
+func (a AnomalyDetection) GetMethod() AnomalyMethod {
+	if a.Method == "" {
+		return AnomalyMethodStdDev
+	}
+	return a.Method
+}
+
+// GetMaxDeviation returns the allowed deviation in standard deviations, defaulting to 3
+func (a AnomalyDetection) GetMaxDeviation() (float64, error) {
+	if a.MaxDeviation == "" {
+		return 3, nil
+	}
+	maxDeviation, err := strconv.ParseFloat(a.MaxDeviation, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maxDeviation %q: %w", a.MaxDeviation, err)
+	}
+	return maxDeviation, nil
+}
+
+func (r RetryStrategy) GetBackoff() BackoffStrategy {
+	if r.Backoff == "" {
+		return BackoffFixed
+	}
+	return r.Backoff
+}
+
+// GetSource returns the name of the provider querying the objective, defaulting to the source of the definition
+func (d KeptnEvaluationDefinition) GetSource(objective Objective) string {
+	if objective.Source != "" {
+		return objective.Source
+	}
+	return d.Spec.Source
+}
+
+// UsesSource returns true if any objective is queried by the source of the definition
+func (s KeptnEvaluationDefinitionSpec) UsesSource() bool {
+	for _, objective := range s.Objectives {
+		if objective.Source == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (d KeptnEvaluationDefinition) IsScoringEnabled() bool {
+	return d.Spec.TotalScore != nil
+}
+
+// GetObservationStart returns the end of the initial delay of an evaluation started at the given time
+func (d KeptnEvaluationDefinition) GetObservationStart(evaluationStart time.Time) time.Time {
+	return evaluationStart.Add(d.Spec.InitialDelay.Duration)
+}
+
+// GetEarliestQueryTime returns the time at which the objectives of an evaluation started at the given time
+// are queried for the first time, after the initial delay and the observation window
+func (d KeptnEvaluationDefinition) GetEarliestQueryTime(evaluationStart time.Time) time.Time {
+	return d.GetObservationStart(evaluationStart).Add(d.Spec.ObservationWindow.Duration)
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestHasKey(t *testing.T) {
@@ -123,3 +124,27 @@ func TestHasKey(t *testing.T) {
 
 	}
 }
+
+func TestKeptnEvaluationProvider_GetType(t *testing.T) {
+	provider := KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "prometheus",
+		},
+	}
+	require.Equal(t, "prometheus", provider.GetType())
+
+	provider.Name = "thanos"
+	provider.Spec.Type = "prometheus"
+	require.Equal(t, "prometheus", provider.GetType())
+}
+
+func TestKeptnEvaluationProvider_IsUnhealthy(t *testing.T) {
+	provider := KeptnEvaluationProvider{}
+	require.False(t, provider.IsUnhealthy())
+
+	provider.Status.Conditions = []metav1.Condition{{Type: ProviderReadyCondition, Status: metav1.ConditionTrue}}
+	require.False(t, provider.IsUnhealthy())
+
+	provider.Status.Conditions = []metav1.Condition{{Type: ProviderReadyCondition, Status: metav1.ConditionFalse}}
+	require.True(t, provider.IsUnhealthy())
+}
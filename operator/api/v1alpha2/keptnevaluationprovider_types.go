@@ -18,6 +18,7 @@ package v1alpha2
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"strings"
 )
@@ -27,20 +28,123 @@ import (
 
 // KeptnEvaluationProviderSpec defines the desired state of KeptnEvaluationProvider
 type KeptnEvaluationProviderSpec struct {
+	// Type of the provider. If it is not set, the name of the provider is used as type,
+	// so that providers named after their type keep working.
+	// +kubebuilder:validation:Enum:=prometheus;dynatrace;datadog;kubernetes;grpc;http;static;alertmanager
+	// +optional
+	Type         string                   `json:"type,omitempty"`
 	TargetServer string                   `json:"targetServer"`
 	SecretKeyRef corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// BasicAuth references the secrets holding the user name and password sent to the prometheus, alertmanager and grpc target servers
+	// +optional
+	BasicAuth *BasicAuth `json:"basicAuth,omitempty"`
+	// TLS configures the certificates used to connect to the prometheus, alertmanager and grpc target servers
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+	// Insecure connects to the grpc target server without TLS, which otherwise verifies the server with the system roots.
+	// The secretKeyRef and basicAuth credentials are never sent over such a connection.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+	// OAuth configures the client credentials used to request an access token for the dynatrace target server,
+	// it replaces the API token referenced by the SecretKeyRef
+	// +optional
+	OAuth *OAuthClientCredentials `json:"oauth,omitempty"`
+	// Headers are added to every request sent to the prometheus, alertmanager and grpc target servers, e.g. X-Scope-OrgID
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// MaxConcurrentQueries limits the number of queries that are sent to the provider at the same time
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MaxConcurrentQueries int `json:"maxConcurrentQueries,omitempty"`
+	// QueryTimeout is the default timeout of the queries sent to the provider, defaults to 20s
+	// +kubebuilder:validation:Pattern="^0|([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +kubebuilder:validation:Type:=string
+	// +optional
+	QueryTimeout metav1.Duration `json:"queryTimeout,omitempty"`
+}
+
+type BasicAuth struct {
+	Username corev1.SecretKeySelector `json:"username"`
+	Password corev1.SecretKeySelector `json:"password"`
+}
+
+type OAuthClientCredentials struct {
+	// TokenURL of the authorization server, defaults to the Dynatrace SSO
+	// +optional
+	TokenURL string `json:"tokenURL,omitempty"`
+	// ClientID references the secret holding the id of the OAuth client
+	ClientID corev1.SecretKeySelector `json:"clientID"`
+	// ClientSecret references the secret holding the secret of the OAuth client
+	ClientSecret corev1.SecretKeySelector `json:"clientSecret"`
+	// Scopes requested for the access token
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+	// Resource is the URN of the Dynatrace account or environment the token is requested for
+	// +optional
+	Resource string `json:"resource,omitempty"`
+}
+
+type TLSConfig struct {
+	// CA references the secret holding the PEM encoded certificate authority used to verify the server
+	// +optional
+	CA *corev1.SecretKeySelector `json:"ca,omitempty"`
+	// Cert references the secret holding the PEM encoded client certificate
+	// +optional
+	Cert *corev1.SecretKeySelector `json:"cert,omitempty"`
+	// Key references the secret holding the PEM encoded private key of the client certificate
+	// +optional
+	Key *corev1.SecretKeySelector `json:"key,omitempty"`
+	// ServerName is used to verify the hostname of the server
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+	// InsecureSkipVerify disables the verification of the server certificate
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
 }
 
 // KeptnEvaluationProviderStatus defines the observed state of KeptnEvaluationProvider
 type KeptnEvaluationProviderStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Reachable reports whether the target server answered the last health check,
+	// it is false for providers that do not support health checks
+	// +optional
+	Reachable bool `json:"reachable,omitempty"`
+	// Latency of the last health check
+	// +optional
+	Latency metav1.Duration `json:"latency,omitempty"`
+	// LastError of the last failed health check
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// LastCheckTime is the time of the last health check
+	// +optional
+	LastCheckTime metav1.Time `json:"lastCheckTime,omitempty"`
+	// Conditions of the provider, the Ready condition reports the result of the health checks
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+const (
+	// ProviderReadyCondition is the type of the condition reporting the health of the provider
+	ProviderReadyCondition = "Ready"
+	// ProviderReachableReason is the reason of the Ready condition if the health check succeeded
+	ProviderReachableReason = "Reachable"
+	// ProviderUnreachableReason is the reason of the Ready condition if the health check failed
+	ProviderUnreachableReason = "Unreachable"
+	// ProviderHealthCheckNotSupportedReason is the reason of the Unknown Ready condition if the type of provider cannot be checked
+	ProviderHealthCheckNotSupportedReason = "HealthCheckNotSupported"
+)
+
+// DefaultMaxConcurrentQueries is the number of queries sent to a provider at the same time if no limit is configured
+const DefaultMaxConcurrentQueries = 5
+
 //+kubebuilder:object:root=true
 //+kubebuilder:storageversion
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:path=keptnevaluationproviders,shortName=kep
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Latency",type=string,JSONPath=`.status.latency`
 
 // KeptnEvaluationProvider is the Schema for the keptnevaluationproviders API
 type KeptnEvaluationProvider struct {
@@ -73,3 +177,30 @@ func (p *KeptnEvaluationProvider) HasSecretDefined() bool {
 	}
 	return true
 }
+
+// GetType returns the type of the provider, defaulting to its name
+func (p *KeptnEvaluationProvider) GetType() string {
+	if p.Spec.Type != "" {
+		return p.Spec.Type
+	}
+	return p.Name
+}
+
+// GetMaxConcurrentQueries returns the maximum number of concurrent queries, defaulting to DefaultMaxConcurrentQueries
+func (p *KeptnEvaluationProvider) GetMaxConcurrentQueries() int {
+	if p.Spec.MaxConcurrentQueries < 1 {
+		return DefaultMaxConcurrentQueries
+	}
+	return p.Spec.MaxConcurrentQueries
+}
+
+// IsUnhealthy returns true if the last health check of the provider failed. Evaluations only skip unhealthy providers,
+// so providers that were not checked yet or do not support health checks, with an Unknown Ready condition, are used.
+func (p *KeptnEvaluationProvider) IsUnhealthy() bool {
+	return meta.IsStatusConditionFalse(p.Status.Conditions, ProviderReadyCondition)
+}
+
+// IsClusterProvider returns true if the provider was converted from a ClusterKeptnEvaluationProvider
+func (p *KeptnEvaluationProvider) IsClusterProvider() bool {
+	return p.Kind == ClusterKeptnEvaluationProviderKind
+}
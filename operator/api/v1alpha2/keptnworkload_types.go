@@ -38,6 +38,9 @@ type KeptnWorkloadSpec struct {
 	PreDeploymentEvaluations  []string          `json:"preDeploymentEvaluations,omitempty"`
 	PostDeploymentEvaluations []string          `json:"postDeploymentEvaluations,omitempty"`
 	ResourceReference         ResourceReference `json:"resourceReference"`
+	// EvaluationFailAction overrides the fail action of the evaluation definitions used by the workload
+	// +optional
+	EvaluationFailAction FailAction `json:"evaluationFailAction,omitempty"`
 }
 
 // KeptnWorkloadStatus defines the observed state of KeptnWorkload
@@ -198,6 +198,7 @@ func TestKeptnWorkloadInstance(t *testing.T) {
 		AppName:              workload.GetAppName(),
 		WorkloadVersion:      workload.GetVersion(),
 		Workload:             workload.GetParentName(),
+		PreviousVersion:      workload.GetPreviousVersion(),
 		EvaluationDefinition: "taskdef",
 		Type:                 common.PostDeploymentCheckType,
 		RetryInterval: metav1.Duration{
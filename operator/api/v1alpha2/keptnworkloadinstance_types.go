@@ -79,6 +79,9 @@ type EvaluationStatus struct {
 	EvaluationName string            `json:"evaluationName,omitempty"`
 	StartTime      metav1.Time       `json:"startTime,omitempty"`
 	EndTime        metav1.Time       `json:"endTime,omitempty"`
+	// FailActionApplied is set if the evaluation failed, but the phase continued because of the fail action
+	// +optional
+	FailActionApplied FailAction `json:"failActionApplied,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -365,11 +368,13 @@ func (w KeptnWorkloadInstance) GenerateEvaluation(evaluationDefinition string, c
 			AppName:              w.GetAppName(),
 			WorkloadVersion:      w.GetVersion(),
 			Workload:             w.GetParentName(),
+			PreviousVersion:      w.Spec.PreviousVersion,
 			EvaluationDefinition: evaluationDefinition,
 			Type:                 checkType,
 			RetryInterval: metav1.Duration{
 				Duration: 5 * time.Second,
 			},
+			FailAction: w.Spec.EvaluationFailAction,
 		},
 	}
 }
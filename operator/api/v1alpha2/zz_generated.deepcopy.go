@@ -24,9 +24,199 @@ package v1alpha2
 import (
 	"github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2/common"
 	"go.opentelemetry.io/otel/propagation"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerQuery) DeepCopyInto(out *AlertmanagerQuery) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerQuery.
+func (in *AlertmanagerQuery) DeepCopy() *AlertmanagerQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnomalyDetection) DeepCopyInto(out *AnomalyDetection) {
+	*out = *in
+	out.Lookback = in.Lookback
+	out.Step = in.Step
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnomalyDetection.
+func (in *AnomalyDetection) DeepCopy() *AnomalyDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(AnomalyDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
+	*out = *in
+	in.Username.DeepCopyInto(&out.Username)
+	in.Password.DeepCopyInto(&out.Password)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicAuth.
+func (in *BasicAuth) DeepCopy() *BasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(BasicAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterKeptnEvaluationDefinition) DeepCopyInto(out *ClusterKeptnEvaluationDefinition) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterKeptnEvaluationDefinition.
+func (in *ClusterKeptnEvaluationDefinition) DeepCopy() *ClusterKeptnEvaluationDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterKeptnEvaluationDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterKeptnEvaluationDefinition) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterKeptnEvaluationDefinitionList) DeepCopyInto(out *ClusterKeptnEvaluationDefinitionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterKeptnEvaluationDefinition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterKeptnEvaluationDefinitionList.
+func (in *ClusterKeptnEvaluationDefinitionList) DeepCopy() *ClusterKeptnEvaluationDefinitionList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterKeptnEvaluationDefinitionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterKeptnEvaluationDefinitionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterKeptnEvaluationProvider) DeepCopyInto(out *ClusterKeptnEvaluationProvider) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterKeptnEvaluationProvider.
+func (in *ClusterKeptnEvaluationProvider) DeepCopy() *ClusterKeptnEvaluationProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterKeptnEvaluationProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterKeptnEvaluationProvider) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterKeptnEvaluationProviderList) DeepCopyInto(out *ClusterKeptnEvaluationProviderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterKeptnEvaluationProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterKeptnEvaluationProviderList.
+func (in *ClusterKeptnEvaluationProviderList) DeepCopy() *ClusterKeptnEvaluationProviderList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterKeptnEvaluationProviderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterKeptnEvaluationProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterKeptnEvaluationProviderSpec) DeepCopyInto(out *ClusterKeptnEvaluationProviderSpec) {
+	*out = *in
+	in.KeptnEvaluationProviderSpec.DeepCopyInto(&out.KeptnEvaluationProviderSpec)
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterKeptnEvaluationProviderSpec.
+func (in *ClusterKeptnEvaluationProviderSpec) DeepCopy() *ClusterKeptnEvaluationProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterKeptnEvaluationProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapReference) DeepCopyInto(out *ConfigMapReference) {
 	*out = *in
@@ -57,6 +247,47 @@ func (in *ContainerSpec) DeepCopy() *ContainerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynatraceQuery) DeepCopyInto(out *DynatraceQuery) {
+	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynatraceQuery.
+func (in *DynatraceQuery) DeepCopy() *DynatraceQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(DynatraceQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvaluationAttempt) DeepCopyInto(out *EvaluationAttempt) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvaluationAttempt.
+func (in *EvaluationAttempt) DeepCopy() *EvaluationAttempt {
+	if in == nil {
+		return nil
+	}
+	out := new(EvaluationAttempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EvaluationStatus) DeepCopyInto(out *EvaluationStatus) {
 	*out = *in
@@ -77,6 +308,20 @@ func (in *EvaluationStatus) DeepCopy() *EvaluationStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EvaluationStatusItem) DeepCopyInto(out *EvaluationStatusItem) {
 	*out = *in
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]EvaluationAttempt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Series != nil {
+		in, out := &in.Series, &out.Series
+		*out = make([]SeriesStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvaluationStatusItem.
@@ -140,6 +385,28 @@ func (in *FunctionStatus) DeepCopy() *FunctionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRequest) DeepCopyInto(out *HTTPRequest) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRequest.
+func (in *HTTPRequest) DeepCopy() *HTTPRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HttpReference) DeepCopyInto(out *HttpReference) {
 	*out = *in
@@ -525,7 +792,21 @@ func (in *KeptnEvaluationDefinitionSpec) DeepCopyInto(out *KeptnEvaluationDefini
 	if in.Objectives != nil {
 		in, out := &in.Objectives, &out.Objectives
 		*out = make([]Objective, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TotalScore != nil {
+		in, out := &in.TotalScore, &out.TotalScore
+		*out = new(TotalScore)
+		**out = **in
+	}
+	out.InitialDelay = in.InitialDelay
+	out.ObservationWindow = in.ObservationWindow
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetryStrategy)
+		**out = **in
 	}
 }
 
@@ -592,7 +873,7 @@ func (in *KeptnEvaluationProvider) DeepCopyInto(out *KeptnEvaluationProvider) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationProvider.
@@ -649,6 +930,29 @@ func (in *KeptnEvaluationProviderList) DeepCopyObject() runtime.Object {
 func (in *KeptnEvaluationProviderSpec) DeepCopyInto(out *KeptnEvaluationProviderSpec) {
 	*out = *in
 	in.SecretKeyRef.DeepCopyInto(&out.SecretKeyRef)
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(BasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OAuth != nil {
+		in, out := &in.OAuth, &out.OAuth
+		*out = new(OAuthClientCredentials)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.QueryTimeout = in.QueryTimeout
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationProviderSpec.
@@ -664,6 +968,15 @@ func (in *KeptnEvaluationProviderSpec) DeepCopy() *KeptnEvaluationProviderSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeptnEvaluationProviderStatus) DeepCopyInto(out *KeptnEvaluationProviderStatus) {
 	*out = *in
+	out.Latency = in.Latency
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeptnEvaluationProviderStatus.
@@ -699,7 +1012,7 @@ func (in *KeptnEvaluationStatus) DeepCopyInto(out *KeptnEvaluationStatus) {
 		in, out := &in.EvaluationStatus, &out.EvaluationStatus
 		*out = make(map[string]EvaluationStatusItem, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
 	in.StartTime.DeepCopyInto(&out.StartTime)
@@ -1170,9 +1483,91 @@ func (in *KeptnWorkloadStatus) DeepCopy() *KeptnWorkloadStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesQuery) DeepCopyInto(out *KubernetesQuery) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesQuery.
+func (in *KubernetesQuery) DeepCopy() *KubernetesQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuthClientCredentials) DeepCopyInto(out *OAuthClientCredentials) {
+	*out = *in
+	in.ClientID.DeepCopyInto(&out.ClientID)
+	in.ClientSecret.DeepCopyInto(&out.ClientSecret)
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuthClientCredentials.
+func (in *OAuthClientCredentials) DeepCopy() *OAuthClientCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuthClientCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Objective) DeepCopyInto(out *Objective) {
 	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Comparison != nil {
+		in, out := &in.Comparison, &out.Comparison
+		*out = new(ObjectiveComparison)
+		**out = **in
+	}
+	if in.Anomaly != nil {
+		in, out := &in.Anomaly, &out.Anomaly
+		*out = new(AnomalyDetection)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPRequest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Range != nil {
+		in, out := &in.Range, &out.Range
+		*out = new(QueryRange)
+		**out = **in
+	}
+	if in.Kubernetes != nil {
+		in, out := &in.Kubernetes, &out.Kubernetes
+		*out = new(KubernetesQuery)
+		**out = **in
+	}
+	if in.Dynatrace != nil {
+		in, out := &in.Dynatrace, &out.Dynatrace
+		*out = new(DynatraceQuery)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Static != nil {
+		in, out := &in.Static, &out.Static
+		*out = new(StaticQuery)
+		**out = **in
+	}
+	if in.Alertmanager != nil {
+		in, out := &in.Alertmanager, &out.Alertmanager
+		*out = new(AlertmanagerQuery)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Objective.
@@ -1185,6 +1580,38 @@ func (in *Objective) DeepCopy() *Objective {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectiveComparison) DeepCopyInto(out *ObjectiveComparison) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectiveComparison.
+func (in *ObjectiveComparison) DeepCopy() *ObjectiveComparison {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectiveComparison)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryRange) DeepCopyInto(out *QueryRange) {
+	*out = *in
+	out.Interval = in.Interval
+	out.Step = in.Step
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryRange.
+func (in *QueryRange) DeepCopy() *QueryRange {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceReference) DeepCopyInto(out *ResourceReference) {
 	*out = *in
@@ -1200,6 +1627,22 @@ func (in *ResourceReference) DeepCopy() *ResourceReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryStrategy) DeepCopyInto(out *RetryStrategy) {
+	*out = *in
+	out.MaxInterval = in.MaxInterval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryStrategy.
+func (in *RetryStrategy) DeepCopy() *RetryStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecureParameters) DeepCopyInto(out *SecureParameters) {
 	*out = *in
@@ -1215,6 +1658,73 @@ func (in *SecureParameters) DeepCopy() *SecureParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeriesStatus) DeepCopyInto(out *SeriesStatus) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeriesStatus.
+func (in *SeriesStatus) DeepCopy() *SeriesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SeriesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaticQuery) DeepCopyInto(out *StaticQuery) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticQuery.
+func (in *StaticQuery) DeepCopy() *StaticQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(StaticQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
+	*out = *in
+	if in.CA != nil {
+		in, out := &in.CA, &out.CA
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cert != nil {
+		in, out := &in.Cert, &out.Cert
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfig.
+func (in *TLSConfig) DeepCopy() *TLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TaskContext) DeepCopyInto(out *TaskContext) {
 	*out = *in
@@ -1269,6 +1779,21 @@ func (in *TaskStatus) DeepCopy() *TaskStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TotalScore) DeepCopyInto(out *TotalScore) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TotalScore.
+func (in *TotalScore) DeepCopy() *TotalScore {
+	if in == nil {
+		return nil
+	}
+	out := new(TotalScore)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadStatus) DeepCopyInto(out *WorkloadStatus) {
 	*out = *in
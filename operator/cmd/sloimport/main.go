@@ -0,0 +1,90 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// sloimport converts Keptn v1 SLI/SLO files and OpenSLO documents into
+// KeptnEvaluationDefinition and KeptnEvaluationProvider manifests
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/keptn/lifecycle-toolkit/operator/pkg/sloimport"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("sloimport", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: sloimport [flags] PATH...\n\n"+
+			"Converts the Keptn v1 SLI/SLO files and OpenSLO documents found at the paths into KeptnEvaluationDefinition\n"+
+			"and KeptnEvaluationProvider manifests. Constructs that cannot be translated are reported on stderr.\n\n")
+		flags.PrintDefaults()
+	}
+	var options sloimport.Options
+	var output string
+	var strict bool
+	flags.StringVar(&options.Namespace, "namespace", "", "namespace of the manifests")
+	flags.StringVar(&options.ProviderType, "provider-type", "prometheus", "provider type of Keptn v1 SLI files that are not in a provider directory")
+	flags.StringVar(&options.TargetServer, "target-server", "", "target server of providers whose address is not part of the files")
+	flags.StringVar(&output, "o", "", "file the manifests are written to, defaults to stdout")
+	flags.BoolVar(&strict, "strict", false, "exit with an error if any construct could not be translated")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	importer := sloimport.NewImporter(options)
+	for _, path := range flags.Args() {
+		if err := importer.AddPath(path); err != nil {
+			fmt.Fprintf(stderr, "error: %s\n", err.Error())
+			return 1
+		}
+	}
+	result := importer.Result()
+	for _, issue := range result.Issues {
+		fmt.Fprintf(stderr, "warning: %s\n", issue.String())
+	}
+
+	manifests, err := result.Manifests()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s\n", err.Error())
+		return 1
+	}
+	if output == "" {
+		_, err = stdout.Write(manifests)
+	} else {
+		err = os.WriteFile(output, manifests, 0o600)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s\n", err.Error())
+		return 1
+	}
+
+	if strict && len(result.Issues) > 0 {
+		return 1
+	}
+	return 0
+}
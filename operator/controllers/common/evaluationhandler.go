@@ -74,7 +74,7 @@ func (r EvaluationHandler) ReconcileEvaluations(ctx context.Context, phaseCtx co
 		}
 
 		// Check if evaluation has already succeeded or failed
-		if evaluationStatus.Status.IsCompleted() {
+		if evaluationStatus.Status.IsCompleted() || evaluationStatus.Status.IsWarning() {
 			newStatus = append(newStatus, evaluationStatus)
 			continue
 		}
@@ -110,14 +110,26 @@ func (r EvaluationHandler) ReconcileEvaluations(ctx context.Context, phaseCtx co
 			}
 			// Update state of Evaluation if it is already created
 			evaluationStatus.Status = evaluation.Status.OverallStatus
-			if evaluationStatus.Status.IsCompleted() {
+			if evaluationStatus.Status.IsCompleted() || evaluationStatus.Status.IsWarning() {
 				if evaluationStatus.Status.IsSucceeded() {
 					spanEvaluationTrace.AddEvent(evaluation.Name + " has finished")
 					spanEvaluationTrace.SetStatus(codes.Ok, "Finished")
 					RecordEvent(r.Recorder, apicommon.PhaseReconcileEvaluation, "Normal", evaluation, "Succeeded", "evaluation succeeded", piWrapper.GetVersion())
+				} else if evaluationStatus.Status.IsWarning() {
+					// the score only reached the warning threshold, the phase succeeds with warnings
+					spanEvaluationTrace.AddEvent(evaluation.Name + " has finished with warnings")
+					spanEvaluationTrace.SetStatus(codes.Ok, "Finished")
+					RecordEvent(r.Recorder, apicommon.PhaseReconcileEvaluation, "Warning", evaluation, "SucceededWithWarnings", "evaluation succeeded with warnings", piWrapper.GetVersion())
+				} else if failAction := evaluation.GetFailAction(); failAction != klcv1alpha2.FailActionFail {
+					// the failure is tolerated, the phase continues as if the evaluation had succeeded
+					spanEvaluationTrace.AddEvent(fmt.Sprintf("%s has failed, continuing because of fail action %s", evaluation.Name, failAction))
+					r.emitEvaluationFailureEvents(evaluation, spanEvaluationTrace, piWrapper, failAction)
+					spanEvaluationTrace.SetStatus(codes.Ok, "Finished")
+					evaluationStatus.Status = apicommon.StateSucceeded
+					evaluationStatus.FailActionApplied = failAction
 				} else {
 					spanEvaluationTrace.AddEvent(evaluation.Name + " has failed")
-					r.emitEvaluationFailureEvents(evaluation, spanEvaluationTrace, piWrapper)
+					r.emitEvaluationFailureEvents(evaluation, spanEvaluationTrace, piWrapper, failAction)
 					spanEvaluationTrace.SetStatus(codes.Error, "Failed")
 				}
 				spanEvaluationTrace.End()
@@ -133,6 +145,9 @@ func (r EvaluationHandler) ReconcileEvaluations(ctx context.Context, phaseCtx co
 
 	for _, ns := range newStatus {
 		summary = apicommon.UpdateStatusSummary(ns.Status, summary)
+		if ns.FailActionApplied == klcv1alpha2.FailActionWarn {
+			summary.Warning++
+		}
 	}
 	if apicommon.GetOverallState(summary) != apicommon.StateSucceeded {
 		RecordEvent(r.Recorder, apicommon.PhaseReconcileEvaluation, "Warning", reconcileObject, "NotFinished", "has not finished", piWrapper.GetVersion())
@@ -149,6 +164,9 @@ func (r EvaluationHandler) CreateKeptnEvaluation(ctx context.Context, namespace
 	phase := apicommon.PhaseCreateEvaluation
 
 	newEvaluation := piWrapper.GenerateEvaluation(evaluationCreateAttributes.EvaluationDefinition, evaluationCreateAttributes.CheckType)
+	if newEvaluation.Spec.FailAction == "" {
+		newEvaluation.Spec.FailAction = r.getDefinitionFailAction(ctx, namespace, evaluationCreateAttributes.EvaluationDefinition)
+	}
 	err = controllerutil.SetControllerReference(reconcileObject, &newEvaluation, r.Scheme)
 	if err != nil {
 		r.Log.Error(err, "could not set controller reference:")
@@ -164,7 +182,19 @@ func (r EvaluationHandler) CreateKeptnEvaluation(ctx context.Context, namespace
 	return newEvaluation.Name, nil
 }
 
-func (r EvaluationHandler) emitEvaluationFailureEvents(evaluation *klcv1alpha2.KeptnEvaluation, spanTrace trace.Span, piWrapper *interfaces.PhaseItemWrapper) {
+// getDefinitionFailAction returns the fail action of the evaluation definition, or an empty one if the definition cannot be fetched
+func (r EvaluationHandler) getDefinitionFailAction(ctx context.Context, namespace string, definitionName string) klcv1alpha2.FailAction {
+	definition, err := GetEvaluationDefinition(ctx, r.Client, definitionName, namespace)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			r.Log.Error(err, "could not fetch KeptnEvaluationDefinition to determine the fail action")
+		}
+		return ""
+	}
+	return definition.Spec.FailAction
+}
+
+func (r EvaluationHandler) emitEvaluationFailureEvents(evaluation *klcv1alpha2.KeptnEvaluation, spanTrace trace.Span, piWrapper *interfaces.PhaseItemWrapper, failAction klcv1alpha2.FailAction) {
 	k8sEventMessage := "evaluation failed"
 	for k, v := range evaluation.Status.EvaluationStatus {
 		if v.Status == apicommon.StateFailed {
@@ -173,5 +203,12 @@ func (r EvaluationHandler) emitEvaluationFailureEvents(evaluation *klcv1alpha2.K
 			k8sEventMessage = fmt.Sprintf("%s\n%s", k8sEventMessage, msg)
 		}
 	}
-	RecordEvent(r.Recorder, apicommon.PhaseReconcileEvaluation, "Warning", evaluation, "Failed", k8sEventMessage, piWrapper.GetVersion())
+	switch failAction {
+	case klcv1alpha2.FailActionWarn:
+		RecordEvent(r.Recorder, apicommon.PhaseReconcileEvaluation, "Warning", evaluation, "FailedWithWarning", k8sEventMessage+"\ncontinuing with a warning because of fail action warn", piWrapper.GetVersion())
+	case klcv1alpha2.FailActionIgnore:
+		RecordEvent(r.Recorder, apicommon.PhaseReconcileEvaluation, "Normal", evaluation, "FailureIgnored", k8sEventMessage+"\ncontinuing because of fail action ignore", piWrapper.GetVersion())
+	default:
+		RecordEvent(r.Recorder, apicommon.PhaseReconcileEvaluation, "Warning", evaluation, "Failed", k8sEventMessage, piWrapper.GetVersion())
+	}
 }
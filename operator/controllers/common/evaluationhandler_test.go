@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -181,6 +182,128 @@ func TestEvaluationHandler(t *testing.T) {
 				"evaluation of 'my-target' failed with value: '1' and reason: 'failed'",
 			},
 		},
+		{
+			name: "failed evaluation with fail action warn",
+			object: &v1alpha2.KeptnAppVersion{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "namespace",
+				},
+				Spec: v1alpha2.KeptnAppVersionSpec{
+					KeptnAppSpec: v1alpha2.KeptnAppSpec{
+						PreDeploymentEvaluations: []string{"eval-def"},
+					},
+				},
+				Status: v1alpha2.KeptnAppVersionStatus{
+					PreDeploymentEvaluationTaskStatus: []v1alpha2.EvaluationStatus{
+						{
+							EvaluationDefinitionName: "eval-def",
+							Status:                   apicommon.StateProgressing,
+							EvaluationName:           "pre-eval-eval-def-",
+						},
+					},
+				},
+			},
+			evalObj: v1alpha2.KeptnEvaluation{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "namespace",
+					Name:      "pre-eval-eval-def-",
+				},
+				Spec: v1alpha2.KeptnEvaluationSpec{
+					FailAction: v1alpha2.FailActionWarn,
+				},
+				Status: v1alpha2.KeptnEvaluationStatus{
+					OverallStatus: apicommon.StateFailed,
+					EvaluationStatus: map[string]v1alpha2.EvaluationStatusItem{
+						"my-target": {
+							Value:   "1",
+							Status:  apicommon.StateFailed,
+							Message: "failed",
+						},
+					},
+				},
+			},
+			createAttr: EvaluationCreateAttributes{
+				SpanName:             "",
+				EvaluationDefinition: "eval-def",
+				CheckType:            apicommon.PreDeploymentEvaluationCheckType,
+			},
+			wantStatus: []v1alpha2.EvaluationStatus{
+				{
+					EvaluationDefinitionName: "eval-def",
+					Status:                   apicommon.StateSucceeded,
+					EvaluationName:           "pre-eval-eval-def-",
+					FailActionApplied:        v1alpha2.FailActionWarn,
+				},
+			},
+			wantSummary:     apicommon.StatusSummary{Total: 1, Succeeded: 1, Warning: 1},
+			wantErr:         nil,
+			getSpanCalls:    1,
+			unbindSpanCalls: 1,
+			events: []string{
+				"ReconcileEvaluationFailedWithWarning",
+			},
+		},
+		{
+			name: "failed evaluation with fail action ignore",
+			object: &v1alpha2.KeptnAppVersion{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "namespace",
+				},
+				Spec: v1alpha2.KeptnAppVersionSpec{
+					KeptnAppSpec: v1alpha2.KeptnAppSpec{
+						PreDeploymentEvaluations: []string{"eval-def"},
+					},
+				},
+				Status: v1alpha2.KeptnAppVersionStatus{
+					PreDeploymentEvaluationTaskStatus: []v1alpha2.EvaluationStatus{
+						{
+							EvaluationDefinitionName: "eval-def",
+							Status:                   apicommon.StateProgressing,
+							EvaluationName:           "pre-eval-eval-def-",
+						},
+					},
+				},
+			},
+			evalObj: v1alpha2.KeptnEvaluation{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "namespace",
+					Name:      "pre-eval-eval-def-",
+				},
+				Spec: v1alpha2.KeptnEvaluationSpec{
+					FailAction: v1alpha2.FailActionIgnore,
+				},
+				Status: v1alpha2.KeptnEvaluationStatus{
+					OverallStatus: apicommon.StateFailed,
+					EvaluationStatus: map[string]v1alpha2.EvaluationStatusItem{
+						"my-target": {
+							Value:   "1",
+							Status:  apicommon.StateFailed,
+							Message: "failed",
+						},
+					},
+				},
+			},
+			createAttr: EvaluationCreateAttributes{
+				SpanName:             "",
+				EvaluationDefinition: "eval-def",
+				CheckType:            apicommon.PreDeploymentEvaluationCheckType,
+			},
+			wantStatus: []v1alpha2.EvaluationStatus{
+				{
+					EvaluationDefinitionName: "eval-def",
+					Status:                   apicommon.StateSucceeded,
+					EvaluationName:           "pre-eval-eval-def-",
+					FailActionApplied:        v1alpha2.FailActionIgnore,
+				},
+			},
+			wantSummary:     apicommon.StatusSummary{Total: 1, Succeeded: 1},
+			wantErr:         nil,
+			getSpanCalls:    1,
+			unbindSpanCalls: 1,
+			events: []string{
+				"ReconcileEvaluationFailureIgnored",
+			},
+		},
 		{
 			name: "succeeded evaluation",
 			object: &v1alpha2.KeptnAppVersion{
@@ -232,6 +355,57 @@ func TestEvaluationHandler(t *testing.T) {
 				"ReconcileEvaluationSucceeded",
 			},
 		},
+		{
+			name: "evaluation succeeded with warnings",
+			object: &v1alpha2.KeptnAppVersion{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "namespace",
+				},
+				Spec: v1alpha2.KeptnAppVersionSpec{
+					KeptnAppSpec: v1alpha2.KeptnAppSpec{
+						PreDeploymentEvaluations: []string{"eval-def"},
+					},
+				},
+				Status: v1alpha2.KeptnAppVersionStatus{
+					PreDeploymentEvaluationTaskStatus: []v1alpha2.EvaluationStatus{
+						{
+							EvaluationDefinitionName: "eval-def",
+							Status:                   apicommon.StateProgressing,
+							EvaluationName:           "pre-eval-eval-def-",
+						},
+					},
+				},
+			},
+			evalObj: v1alpha2.KeptnEvaluation{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "namespace",
+					Name:      "pre-eval-eval-def-",
+				},
+				Status: v1alpha2.KeptnEvaluationStatus{
+					OverallStatus: apicommon.StateWarning,
+					Score:         "80",
+				},
+			},
+			createAttr: EvaluationCreateAttributes{
+				SpanName:             "",
+				EvaluationDefinition: "eval-def",
+				CheckType:            apicommon.PreDeploymentEvaluationCheckType,
+			},
+			wantStatus: []v1alpha2.EvaluationStatus{
+				{
+					EvaluationDefinitionName: "eval-def",
+					Status:                   apicommon.StateWarning,
+					EvaluationName:           "pre-eval-eval-def-",
+				},
+			},
+			wantSummary:     apicommon.StatusSummary{Total: 1, Succeeded: 1, Warning: 1},
+			wantErr:         nil,
+			getSpanCalls:    1,
+			unbindSpanCalls: 1,
+			events: []string{
+				"ReconcileEvaluationSucceededWithWarnings",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -261,6 +435,7 @@ func TestEvaluationHandler(t *testing.T) {
 					require.Equal(t, tt.wantStatus[j].EvaluationDefinitionName, item.EvaluationDefinitionName)
 					require.True(t, strings.Contains(item.EvaluationName, tt.wantStatus[j].EvaluationName))
 					require.Equal(t, tt.wantStatus[j].Status, item.Status)
+					require.Equal(t, tt.wantStatus[j].FailActionApplied, item.FailActionApplied)
 				}
 			} else {
 				t.Errorf("unexpected result, want %+v, got %+v", tt.wantStatus, status)
@@ -338,3 +513,67 @@ func TestEvaluationHandler_createEvaluation(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluationHandler_createEvaluationFailAction(t *testing.T) {
+	definition := &v1alpha2.KeptnEvaluationDefinition{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "namespace",
+			Name:      "eval-def",
+		},
+		Spec: v1alpha2.KeptnEvaluationDefinitionSpec{
+			FailAction: v1alpha2.FailActionIgnore,
+		},
+	}
+	tests := []struct {
+		name           string
+		workloadAction v1alpha2.FailAction
+		want           v1alpha2.FailAction
+	}{
+		{
+			name: "fail action of the definition",
+			want: v1alpha2.FailActionIgnore,
+		},
+		{
+			name:           "fail action of the workload overrides the definition",
+			workloadAction: v1alpha2.FailActionWarn,
+			want:           v1alpha2.FailActionWarn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v1alpha2.AddToScheme(scheme.Scheme)
+			require.Nil(t, err)
+			fakeClient := fake.NewClientBuilder().WithObjects(definition).Build()
+			handler := EvaluationHandler{
+				SpanHandler: &kltfake.ISpanHandlerMock{},
+				Log:         ctrl.Log.WithName("controller"),
+				Recorder:    record.NewFakeRecorder(100),
+				Client:      fakeClient,
+				Tracer:      trace.NewNoopTracerProvider().Tracer("tracer"),
+				Scheme:      scheme.Scheme,
+			}
+			workloadInstance := &v1alpha2.KeptnWorkloadInstance{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace: "namespace",
+				},
+				Spec: v1alpha2.KeptnWorkloadInstanceSpec{
+					KeptnWorkloadSpec: v1alpha2.KeptnWorkloadSpec{
+						PreDeploymentEvaluations: []string{"eval-def"},
+						EvaluationFailAction:     tt.workloadAction,
+					},
+				},
+			}
+			name, err := handler.CreateKeptnEvaluation(context.TODO(), "namespace", workloadInstance, EvaluationCreateAttributes{
+				EvaluationDefinition: "eval-def",
+				CheckType:            apicommon.PreDeploymentEvaluationCheckType,
+			})
+			require.Nil(t, err)
+
+			evaluation := &v1alpha2.KeptnEvaluation{}
+			err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "namespace", Name: name}, evaluation)
+			require.Nil(t, err)
+			require.Equal(t, tt.want, evaluation.Spec.FailAction)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetEvaluationDefinition returns the KeptnEvaluationDefinition with the given name in the namespace,
+// falling back to the ClusterKeptnEvaluationDefinition of the same name
+func GetEvaluationDefinition(ctx context.Context, c client.Client, name string, namespace string) (*klcv1alpha2.KeptnEvaluationDefinition, error) {
+	definition := &klcv1alpha2.KeptnEvaluationDefinition{}
+	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, definition)
+	if err == nil || !errors.IsNotFound(err) {
+		return definition, err
+	}
+
+	clusterDefinition := &klcv1alpha2.ClusterKeptnEvaluationDefinition{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, clusterDefinition); err != nil {
+		return nil, err
+	}
+	*definition = clusterDefinition.ToEvaluationDefinition(namespace)
+	return definition, nil
+}
+
+// GetEvaluationProvider returns the KeptnEvaluationProvider with the given name in the namespace,
+// falling back to the ClusterKeptnEvaluationProvider of the same name if the namespace may use it.
+// The secrets of a cluster provider are read from the secretNamespace, never from the namespace of the evaluation.
+func GetEvaluationProvider(ctx context.Context, c client.Client, name string, namespace string, secretNamespace string) (*klcv1alpha2.KeptnEvaluationProvider, error) {
+	provider := &klcv1alpha2.KeptnEvaluationProvider{}
+	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, provider)
+	if err == nil || !errors.IsNotFound(err) {
+		return provider, err
+	}
+
+	clusterProvider := &klcv1alpha2.ClusterKeptnEvaluationProvider{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, clusterProvider); err != nil {
+		return nil, err
+	}
+	allowed, err := isNamespaceSelected(ctx, c, clusterProvider.Spec.NamespaceSelector, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("could not use ClusterKeptnEvaluationProvider %s in namespace %s: %w", name, namespace, controllererrors.ErrClusterProviderNotAllowed)
+	}
+	*provider = clusterProvider.ToEvaluationProvider(secretNamespace)
+	return provider, nil
+}
+
+// isNamespaceSelected returns true if the labels of the namespace match the selector, every namespace matches a nil selector
+func isNamespaceSelected(ctx context.Context, c client.Client, selector *metav1.LabelSelector, namespace string) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, err
+	}
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return false, err
+	}
+	return labelSelector.Matches(labels.Set(ns.Labels)), nil
+}
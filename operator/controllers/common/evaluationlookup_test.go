@@ -0,0 +1,77 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_GetEvaluationDefinition(t *testing.T) {
+	definition := &klcv1alpha2.KeptnEvaluationDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "team-a"},
+		Spec:       klcv1alpha2.KeptnEvaluationDefinitionSpec{Source: "namespaced"},
+	}
+	clusterDefinition := &klcv1alpha2.ClusterKeptnEvaluationDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+		Spec:       klcv1alpha2.KeptnEvaluationDefinitionSpec{Source: "cluster"},
+	}
+	fakeClient, err := fake.NewClient(definition, clusterDefinition)
+	require.Nil(t, err)
+
+	// the namespaced definition takes precedence
+	result, err := GetEvaluationDefinition(context.TODO(), fakeClient, "shared", "team-a")
+	require.Nil(t, err)
+	require.Equal(t, "namespaced", result.Spec.Source)
+
+	result, err = GetEvaluationDefinition(context.TODO(), fakeClient, "shared", "team-b")
+	require.Nil(t, err)
+	require.Equal(t, "cluster", result.Spec.Source)
+	require.Equal(t, "team-b", result.Namespace)
+
+	_, err = GetEvaluationDefinition(context.TODO(), fakeClient, "missing", "team-a")
+	require.True(t, k8serrors.IsNotFound(err))
+}
+
+func Test_GetEvaluationProvider(t *testing.T) {
+	provider := &klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus", Namespace: "team-a"},
+		Spec:       klcv1alpha2.KeptnEvaluationProviderSpec{TargetServer: "http://team-a-prometheus:9090"},
+	}
+	clusterProvider := &klcv1alpha2.ClusterKeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus"},
+		Spec: klcv1alpha2.ClusterKeptnEvaluationProviderSpec{
+			KeptnEvaluationProviderSpec: klcv1alpha2.KeptnEvaluationProviderSpec{TargetServer: "http://prometheus:9090"},
+			NamespaceSelector:           &metav1.LabelSelector{MatchLabels: map[string]string{"metrics": "shared"}},
+		},
+	}
+	teamB := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"metrics": "shared"}}}
+	teamC := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-c"}}
+	fakeClient, err := fake.NewClient(provider, clusterProvider, teamB, teamC)
+	require.Nil(t, err)
+
+	// the namespaced provider takes precedence
+	result, err := GetEvaluationProvider(context.TODO(), fakeClient, "prometheus", "team-a", "keptn-lifecycle-toolkit-system")
+	require.Nil(t, err)
+	require.Equal(t, "http://team-a-prometheus:9090", result.Spec.TargetServer)
+	require.Equal(t, "team-a", result.Namespace)
+
+	// the secrets of the cluster provider are read from the secret namespace only
+	result, err = GetEvaluationProvider(context.TODO(), fakeClient, "prometheus", "team-b", "keptn-lifecycle-toolkit-system")
+	require.Nil(t, err)
+	require.Equal(t, "http://prometheus:9090", result.Spec.TargetServer)
+	require.Equal(t, "keptn-lifecycle-toolkit-system", result.Namespace)
+
+	_, err = GetEvaluationProvider(context.TODO(), fakeClient, "prometheus", "team-c", "keptn-lifecycle-toolkit-system")
+	require.True(t, errors.Is(err, controllererrors.ErrClusterProviderNotAllowed))
+
+	_, err = GetEvaluationProvider(context.TODO(), fakeClient, "dynatrace", "team-a", "keptn-lifecycle-toolkit-system")
+	require.True(t, k8serrors.IsNotFound(err))
+}
@@ -63,6 +63,12 @@ func (r PhaseHandler) HandlePhase(ctx context.Context, ctxTrace context.Context,
 		state = apicommon.StateProgressing
 	}
 
+	// a phase with tolerated failures succeeds, but reports a warning
+	succeededWithWarnings := state.IsWarning()
+	if succeededWithWarnings {
+		state = apicommon.StateSucceeded
+	}
+
 	defer func(ctx context.Context, oldStatus apicommon.KeptnState, oldPhase string, reconcileObject client.Object) {
 		piWrapper, _ := interfaces.NewPhaseItemWrapperFromClientObject(reconcileObject)
 		if oldStatus != piWrapper.GetState() || oldPhase != piWrapper.GetCurrentPhase() {
@@ -88,13 +94,21 @@ func (r PhaseHandler) HandlePhase(ctx context.Context, ctxTrace context.Context,
 		}
 
 		piWrapper.SetState(apicommon.StateSucceeded)
-		spanPhaseTrace.AddEvent(phase.LongName + " has succeeded")
+		if succeededWithWarnings {
+			spanPhaseTrace.AddEvent(phase.LongName + " has succeeded with warnings")
+		} else {
+			spanPhaseTrace.AddEvent(phase.LongName + " has succeeded")
+		}
 		spanPhaseTrace.SetStatus(codes.Ok, "Succeeded")
 		spanPhaseTrace.End()
 		if err := r.SpanHandler.UnbindSpan(reconcileObject, phase.ShortName); err != nil {
 			r.Log.Error(err, controllererrors.ErrCouldNotUnbindSpan, reconcileObject.GetName())
 		}
-		RecordEvent(r.Recorder, phase, "Normal", reconcileObject, "Succeeded", "has succeeded", piWrapper.GetVersion())
+		if succeededWithWarnings {
+			RecordEvent(r.Recorder, phase, "Warning", reconcileObject, "SucceededWithWarnings", "has succeeded with warnings", piWrapper.GetVersion())
+		} else {
+			RecordEvent(r.Recorder, phase, "Normal", reconcileObject, "Succeeded", "has succeeded", piWrapper.GetVersion())
+		}
 
 		return &PhaseResult{Continue: true, Result: requeueResult}, nil
 	}
@@ -156,6 +156,33 @@ func TestPhaseHandler(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "reconcilePhase warning state",
+			handler: PhaseHandler{
+				SpanHandler: &SpanHandler{},
+				Log:         ctrl.Log.WithName("controller"),
+				Recorder:    record.NewFakeRecorder(100),
+				Client:      fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+			},
+			object: &v1alpha2.KeptnAppVersion{
+				Status: v1alpha2.KeptnAppVersionStatus{
+					Status:       apicommon.StateProgressing,
+					CurrentPhase: apicommon.PhaseAppPreEvaluation.LongName,
+				},
+			},
+			phase: apicommon.PhaseAppPreEvaluation,
+			reconcilePhase: func(phaseCtx context.Context) (apicommon.KeptnState, error) {
+				return apicommon.StateWarning, nil
+			},
+			want:    &PhaseResult{Continue: true, Result: requeueResult},
+			wantErr: nil,
+			wantObject: &v1alpha2.KeptnAppVersion{
+				Status: v1alpha2.KeptnAppVersionStatus{
+					Status:       apicommon.StateSucceeded,
+					CurrentPhase: apicommon.PhaseAppPreEvaluation.ShortName,
+				},
+			},
+		},
 		{
 			name: "reconcilePhase failed state",
 			handler: PhaseHandler{
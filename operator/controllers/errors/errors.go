@@ -10,7 +10,15 @@ var ErrCannotWrapToSpanItem = fmt.Errorf("provided object does not implement Spa
 var ErrRetryCountExceeded = fmt.Errorf("retryCount for evaluation exceeded")
 var ErrNoValues = fmt.Errorf("no values")
 var ErrInvalidOperator = fmt.Errorf("invalid operator")
+var ErrInvalidEvaluationTarget = fmt.Errorf("invalid evaluation target")
 var ErrCannotMarshalParams = fmt.Errorf("could not marshal parameters")
+var ErrMultiSeriesNotSupported = fmt.Errorf("the provider does not support results with multiple series")
+var ErrAnomalyDetectionNotSupported = fmt.Errorf("the provider does not support anomaly detection")
+var ErrComparisonQueryNotVersioned = fmt.Errorf("the query does not use the {{.WorkloadVersion}} or {{.AppVersion}} variable, a previousVersionQuery is required to compare it with the previous version")
+var ErrSensitiveKindNotAllowed = fmt.Errorf("objects of this kind cannot be read by objectives")
+var ErrKubernetesNamespaceNotAllowed = fmt.Errorf("objects can only be read from the namespace of the evaluation")
+var ErrNoEvaluationProvider = fmt.Errorf("neither the objective nor its definition sets a source")
+var ErrClusterProviderNotAllowed = fmt.Errorf("the namespace is not selected by the namespaceSelector of the provider")
 var ErrUnsupportedWorkloadInstanceResourceReference = fmt.Errorf("unsupported Resource Reference")
 
 var ErrCannotRetrieveInstancesMsg = "could not retrieve instances: %w"
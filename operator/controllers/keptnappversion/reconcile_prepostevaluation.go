@@ -45,5 +45,5 @@ func (r *KeptnAppVersionReconciler) reconcilePrePostEvaluation(ctx context.Conte
 	if err != nil {
 		return apicommon.StateUnknown, err
 	}
-	return overallState, nil
+	return apicommon.GetOverallStateWithWarnings(state), nil
 }
@@ -0,0 +1,110 @@
+package keptnevaluation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluation/providers"
+)
+
+// madScale scales the median absolute deviation to the standard deviation of normally distributed values
+const madScale = 1.4826
+
+// baseline is the center and the spread of the values of a query in the lookback window of an objective
+type baseline struct {
+	center float64
+	spread float64
+}
+
+// fetchBaseline queries the values of the lookback window of the objective and computes their baseline
+func fetchBaseline(ctx context.Context, provider providers.KeptnSLIProvider, objective klcv1alpha2.Objective, evaluationProvider klcv1alpha2.KeptnEvaluationProvider) (baseline, error) {
+	baselineProvider, ok := provider.(providers.KeptnSLIProviderBaseline)
+	if !ok {
+		return baseline{}, controllererrors.ErrAnomalyDetectionNotSupported
+	}
+	values, err := baselineProvider.EvaluateBaselineQuery(ctx, objective, evaluationProvider)
+	if err != nil {
+		return baseline{}, err
+	}
+	return computeBaseline(objective.Anomaly.GetMethod(), values)
+}
+
+// computeBaseline returns the mean and the standard deviation of the values,
+// or their median and their median absolute deviation scaled to the standard deviation
+func computeBaseline(method klcv1alpha2.AnomalyMethod, values []float64) (baseline, error) {
+	finite := make([]float64, 0, len(values))
+	for _, v := range values {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			finite = append(finite, v)
+		}
+	}
+	if len(finite) == 0 {
+		return baseline{}, fmt.Errorf("no values in the baseline query result")
+	}
+
+	switch method {
+	case klcv1alpha2.AnomalyMethodStdDev, "":
+		var sum float64
+		for _, v := range finite {
+			sum += v
+		}
+		mean := sum / float64(len(finite))
+		var squares float64
+		for _, v := range finite {
+			squares += (v - mean) * (v - mean)
+		}
+		return baseline{center: mean, spread: math.Sqrt(squares / float64(len(finite)))}, nil
+	case klcv1alpha2.AnomalyMethodMAD:
+		center := median(finite)
+		deviations := make([]float64, 0, len(finite))
+		for _, v := range finite {
+			deviations = append(deviations, math.Abs(v-center))
+		}
+		return baseline{center: center, spread: median(deviations) * madScale}, nil
+	default:
+		return baseline{}, fmt.Errorf("anomaly detection method %s not supported", method)
+	}
+}
+
+func median(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// deviation returns the signed distance of the value from the center of the baseline in standard deviations.
+// If the baseline has no spread, any other value than its center deviates infinitely.
+func (b baseline) deviation(value string) (float64, error) {
+	current, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse query result %q: %w", value, err)
+	}
+	difference := current - b.center
+	if b.spread == 0 {
+		if difference == 0 {
+			return 0, nil
+		}
+		return math.Inf(int(math.Copysign(1, difference))), nil
+	}
+	return difference / b.spread, nil
+}
+
+// getDeviationTarget returns the target the deviation of an objective detecting anomalies is checked against
+func getDeviationTarget(anomaly klcv1alpha2.AnomalyDetection) (string, error) {
+	maxDeviation, err := anomaly.GetMaxDeviation()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("between %s and %s", formatTargetNumber(-maxDeviation), formatTargetNumber(maxDeviation)), nil
+}
@@ -0,0 +1,209 @@
+package keptnevaluation
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	apicommon "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2/common"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// fakeBaselineProvider returns the same value and baseline values for every query
+type fakeBaselineProvider struct {
+	value    string
+	baseline []float64
+}
+
+func (f *fakeBaselineProvider) EvaluateQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	return f.value, nil
+}
+
+func (f *fakeBaselineProvider) EvaluateBaselineQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) ([]float64, error) {
+	return f.baseline, nil
+}
+
+func TestComputeBaseline(t *testing.T) {
+	tests := []struct {
+		name   string
+		method klcv1alpha2.AnomalyMethod
+		values []float64
+		want   baseline
+		err    bool
+	}{
+		{
+			name:   "stddev",
+			method: klcv1alpha2.AnomalyMethodStdDev,
+			values: []float64{2, 4, 4, 4, 5, 5, 7, 9},
+			want:   baseline{center: 5, spread: 2},
+		},
+		{
+			name:   "default method",
+			values: []float64{3, 3, 3},
+			want:   baseline{center: 3, spread: 0},
+		},
+		{
+			name:   "mad ignores outliers",
+			method: klcv1alpha2.AnomalyMethodMAD,
+			values: []float64{1, 2, 3, 4, 1000},
+			want:   baseline{center: 3, spread: madScale},
+		},
+		{
+			name:   "mad with even number of values",
+			method: klcv1alpha2.AnomalyMethodMAD,
+			values: []float64{1, 2, 4, 5},
+			want:   baseline{center: 3, spread: 1.5 * madScale},
+		},
+		{
+			name:   "NaN values are skipped",
+			method: klcv1alpha2.AnomalyMethodStdDev,
+			values: []float64{math.NaN(), 4, 6},
+			want:   baseline{center: 5, spread: 1},
+		},
+		{
+			name:   "no values",
+			method: klcv1alpha2.AnomalyMethodStdDev,
+			values: []float64{math.NaN()},
+			err:    true,
+		},
+		{
+			name:   "unknown method",
+			method: "iqr",
+			values: []float64{1},
+			err:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := computeBaseline(tt.method, tt.values)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.InDelta(t, tt.want.center, got.center, 1e-9)
+			require.InDelta(t, tt.want.spread, got.spread, 1e-9)
+		})
+	}
+}
+
+func TestBaselineDeviation(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseline baseline
+		value    string
+		want     float64
+		err      bool
+	}{
+		{name: "above", baseline: baseline{center: 5, spread: 2}, value: "11", want: 3},
+		{name: "below", baseline: baseline{center: 5, spread: 2}, value: "4", want: -0.5},
+		{name: "no spread", baseline: baseline{center: 5}, value: "5", want: 0},
+		{name: "no spread above", baseline: baseline{center: 5}, value: "6", want: math.Inf(1)},
+		{name: "no spread below", baseline: baseline{center: 5}, value: "4", want: math.Inf(-1)},
+		{name: "invalid value", baseline: baseline{center: 5, spread: 2}, value: "five", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.baseline.deviation(tt.value)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestKeptnEvaluationReconciler_AnomalyDetection(t *testing.T) {
+	r := &KeptnEvaluationReconciler{
+		Log: ctrl.Log.WithName("testytest"),
+	}
+	evaluation := &klcv1alpha2.KeptnEvaluation{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-evaluation", Namespace: "default"},
+		Status:     klcv1alpha2.KeptnEvaluationStatus{StartTime: metav1.NewTime(time.Now())},
+	}
+	definition := &klcv1alpha2.KeptnEvaluationDefinition{}
+	baselineValues := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+
+	tests := []struct {
+		name      string
+		provider  *fakeBaselineProvider
+		anomaly   klcv1alpha2.AnomalyDetection
+		target    string
+		status    apicommon.KeptnState
+		baseline  string
+		deviation string
+		message   string
+	}{
+		{
+			name:      "within the allowed deviation",
+			provider:  &fakeBaselineProvider{value: "10", baseline: baselineValues},
+			target:    "<100",
+			status:    apicommon.StateSucceeded,
+			baseline:  "5",
+			deviation: "2.5",
+		},
+		{
+			name:      "deviates too much",
+			provider:  &fakeBaselineProvider{value: "12", baseline: baselineValues},
+			target:    "<100",
+			status:    apicommon.StateFailed,
+			baseline:  "5",
+			deviation: "3.5",
+			message:   "value 12 deviates 3.5 standard deviations from the baseline 5 (standard deviation 2), allowed: between -3 and 3",
+		},
+		{
+			name:      "custom deviation",
+			provider:  &fakeBaselineProvider{value: "1", baseline: baselineValues},
+			anomaly:   klcv1alpha2.AnomalyDetection{MaxDeviation: "1.5"},
+			target:    "<100",
+			status:    apicommon.StateFailed,
+			baseline:  "5",
+			deviation: "-2",
+		},
+		{
+			name:      "targets are still checked",
+			provider:  &fakeBaselineProvider{value: "6", baseline: baselineValues},
+			target:    "<5",
+			status:    apicommon.StateFailed,
+			baseline:  "5",
+			deviation: "0.5",
+		},
+		{
+			name:     "no baseline",
+			provider: &fakeBaselineProvider{value: "6"},
+			target:   "<100",
+			status:   apicommon.StateFailed,
+			message:  "could not retrieve the baseline: no values in the baseline query result",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anomaly := tt.anomaly
+			objective := klcv1alpha2.Objective{
+				Name:             "latency",
+				Query:            "latency",
+				EvaluationTarget: tt.target,
+				Anomaly:          &anomaly,
+			}
+			statusItem := r.evaluateObjective(context.TODO(), tt.provider, evaluation, definition, objective, klcv1alpha2.KeptnEvaluationProvider{})
+			require.Equal(t, tt.status, statusItem.Status)
+			require.Equal(t, tt.baseline, statusItem.Baseline)
+			require.Equal(t, tt.deviation, statusItem.Deviation)
+			if tt.message != "" {
+				require.Equal(t, tt.message, statusItem.Message)
+			}
+		})
+	}
+}
+
+func TestFetchBaseline_NotSupported(t *testing.T) {
+	objective := klcv1alpha2.Objective{Query: "latency", Anomaly: &klcv1alpha2.AnomalyDetection{}}
+	_, err := fetchBaseline(context.TODO(), &fakeSLIProvider{value: "1"}, objective, klcv1alpha2.KeptnEvaluationProvider{})
+	require.NotNil(t, err)
+}
@@ -0,0 +1,56 @@
+package keptnevaluation
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+)
+
+// getRetryInterval returns the time to wait after the given attempt, starting at 1, before the evaluation is retried
+func getRetryInterval(retry *klcv1alpha2.RetryStrategy, retryInterval time.Duration, attempt int) time.Duration {
+	if retry == nil {
+		return retryInterval
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	maxInterval := time.Duration(math.MaxInt64)
+	if retry.MaxInterval.Duration > 0 {
+		maxInterval = retry.MaxInterval.Duration
+	}
+
+	interval := retryInterval
+	switch retry.GetBackoff() {
+	case klcv1alpha2.BackoffLinear:
+		if retryInterval > 0 && int64(attempt) > int64(maxInterval/retryInterval) {
+			interval = maxInterval
+		} else {
+			interval = retryInterval * time.Duration(attempt)
+		}
+	case klcv1alpha2.BackoffExponential:
+		for i := 1; i < attempt && interval < maxInterval; i++ {
+			if interval > maxInterval/2 {
+				interval = maxInterval
+				break
+			}
+			interval *= 2
+		}
+	}
+	if interval > maxInterval {
+		interval = maxInterval
+	}
+
+	return addJitter(interval, retry.Jitter)
+}
+
+// addJitter randomly changes the interval by up to the given percentage in both directions
+func addJitter(interval time.Duration, jitter int) time.Duration {
+	if jitter <= 0 || interval <= 0 {
+		return interval
+	}
+	maxJitter := float64(interval) * float64(jitter) / 100
+	return interval + time.Duration((rand.Float64()*2-1)*maxJitter)
+}
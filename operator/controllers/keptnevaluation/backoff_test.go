@@ -0,0 +1,93 @@
+package keptnevaluation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetRetryInterval(t *testing.T) {
+	tests := []struct {
+		name    string
+		retry   *klcv1alpha2.RetryStrategy
+		attempt int
+		want    time.Duration
+	}{
+		{
+			name:    "no retry strategy",
+			attempt: 5,
+			want:    5 * time.Second,
+		},
+		{
+			name:    "fixed",
+			retry:   &klcv1alpha2.RetryStrategy{Backoff: klcv1alpha2.BackoffFixed},
+			attempt: 5,
+			want:    5 * time.Second,
+		},
+		{
+			name:    "linear",
+			retry:   &klcv1alpha2.RetryStrategy{Backoff: klcv1alpha2.BackoffLinear},
+			attempt: 3,
+			want:    15 * time.Second,
+		},
+		{
+			name: "linear with cap",
+			retry: &klcv1alpha2.RetryStrategy{
+				Backoff:     klcv1alpha2.BackoffLinear,
+				MaxInterval: metav1.Duration{Duration: 12 * time.Second},
+			},
+			attempt: 3,
+			want:    12 * time.Second,
+		},
+		{
+			name:    "exponential first attempt",
+			retry:   &klcv1alpha2.RetryStrategy{Backoff: klcv1alpha2.BackoffExponential},
+			attempt: 1,
+			want:    5 * time.Second,
+		},
+		{
+			name:    "exponential",
+			retry:   &klcv1alpha2.RetryStrategy{Backoff: klcv1alpha2.BackoffExponential},
+			attempt: 4,
+			want:    40 * time.Second,
+		},
+		{
+			name: "exponential with cap",
+			retry: &klcv1alpha2.RetryStrategy{
+				Backoff:     klcv1alpha2.BackoffExponential,
+				MaxInterval: metav1.Duration{Duration: time.Minute},
+			},
+			attempt: 100,
+			want:    time.Minute,
+		},
+		{
+			name:    "exponential without cap does not overflow",
+			retry:   &klcv1alpha2.RetryStrategy{Backoff: klcv1alpha2.BackoffExponential},
+			attempt: 1000,
+			want:    time.Duration(math.MaxInt64),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getRetryInterval(tt.retry, 5*time.Second, tt.attempt)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetRetryInterval_Jitter(t *testing.T) {
+	retry := &klcv1alpha2.RetryStrategy{
+		Backoff: klcv1alpha2.BackoffFixed,
+		Jitter:  20,
+	}
+	for i := 0; i < 100; i++ {
+		interval := getRetryInterval(retry, 10*time.Second, 1)
+		require.GreaterOrEqual(t, interval, 8*time.Second)
+		require.LessOrEqual(t, interval, 12*time.Second)
+	}
+}
@@ -6,34 +6,68 @@ import (
 	"strconv"
 
 	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	apicommon "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2/common"
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
 )
 
 func checkValue(objective klcv1alpha2.Objective, item *klcv1alpha2.EvaluationStatusItem) (bool, error) {
+	return checkTarget(objective.EvaluationTarget, item)
+}
 
-	if len(item.Value) == 0 || len(objective.EvaluationTarget) == 0 {
-		return false, fmt.Errorf("no values")
-	}
+func checkTarget(evaluationTarget string, item *klcv1alpha2.EvaluationStatusItem) (bool, error) {
 
-	eval := objective.EvaluationTarget[1:]
-	sign := objective.EvaluationTarget[:1]
+	if len(item.Value) == 0 || len(evaluationTarget) == 0 {
+		return false, controllererrors.ErrNoValues
+	}
 
 	resultValue, err := strconv.ParseFloat(item.Value, 64)
-	if err != nil || math.IsNaN(resultValue) {
-		return false, err
+	if err != nil {
+		return false, fmt.Errorf("could not parse query result %q: %w", item.Value, err)
+	}
+	if math.IsNaN(resultValue) {
+		return false, nil
 	}
 
-	compareValue, err := strconv.ParseFloat(eval, 64)
-	if err != nil || math.IsNaN(compareValue) {
+	target, err := ParseTarget(evaluationTarget)
+	if err != nil {
 		return false, err
 	}
 
-	// choose comparator
-	switch sign {
-	case ">":
-		return resultValue > compareValue, nil
-	case "<":
-		return resultValue < compareValue, nil
-	default:
-		return false, fmt.Errorf("invalid operator")
+	return target.Evaluate(resultValue), nil
+}
+
+// computeScore calculates the total score of the objectives in percent and stores the
+// contribution of every objective in its status item. Objectives that passed contribute
+// their full weight, objectives that only met their warning target contribute half of it.
+// The returned bool is false if any key SLI did not pass.
+func computeScore(objectives []klcv1alpha2.Objective, statuses map[string]klcv1alpha2.EvaluationStatusItem) (float64, bool) {
+	keySLIsPassed := true
+	maxScore := 0.0
+	score := 0.0
+	for _, objective := range objectives {
+		weight := float64(objective.GetWeight())
+		maxScore += weight
+		item := statuses[objective.Name]
+		contribution := 0.0
+		switch item.Status {
+		case apicommon.StateSucceeded:
+			contribution = weight
+		case apicommon.StateWarning:
+			contribution = weight / 2
+		}
+		if objective.KeySLI && !item.Status.IsSucceeded() {
+			keySLIsPassed = false
+		}
+		score += contribution
+		item.Score = formatScore(contribution)
+		statuses[objective.Name] = item
+	}
+	if maxScore == 0 {
+		return 0, keySLIsPassed
 	}
+	return score / maxScore * 100, keySLIsPassed
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', 2, 64)
 }
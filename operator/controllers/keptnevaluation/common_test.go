@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	apicommon "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2/common"
 	"github.com/stretchr/testify/require"
 )
 
@@ -172,6 +173,71 @@ func TestCheckValue(t *testing.T) {
 			result: false,
 			err:    false,
 		},
+		{
+			name: "10>=10",
+			obj: klcv1alpha2.Objective{
+				Name:             "testytest",
+				Query:            "mymetric",
+				EvaluationTarget: ">=10",
+			},
+			item: &klcv1alpha2.EvaluationStatusItem{
+				Value: "10",
+			},
+			result: true,
+			err:    false,
+		},
+		{
+			name: "10!=10",
+			obj: klcv1alpha2.Objective{
+				Name:             "testytest",
+				Query:            "mymetric",
+				EvaluationTarget: "!=10",
+			},
+			item: &klcv1alpha2.EvaluationStatusItem{
+				Value: "10",
+			},
+			result: false,
+			err:    false,
+		},
+		{
+			name: "150 between 100 and 200",
+			obj: klcv1alpha2.Objective{
+				Name:             "testytest",
+				Query:            "mymetric",
+				EvaluationTarget: "between 100 and 200",
+			},
+			item: &klcv1alpha2.EvaluationStatusItem{
+				Value: "150",
+			},
+			result: true,
+			err:    false,
+		},
+		{
+			name: "compound target",
+			obj: klcv1alpha2.Objective{
+				Name:             "testytest",
+				Query:            "mymetric",
+				EvaluationTarget: "<5 or >=100 and <=200",
+			},
+			item: &klcv1alpha2.EvaluationStatusItem{
+				Value: "250",
+			},
+			result: false,
+			err:    false,
+		},
+		{
+			name: "typo in operator",
+			obj: klcv1alpha2.Objective{
+				Name:             "testytest",
+				Query:            "mymetric",
+				EvaluationTarget: "=>5",
+			},
+			item: &klcv1alpha2.EvaluationStatusItem{
+				Value: "10",
+			},
+			result: false,
+			err:    true,
+		},
 		{
 			name: "invalid op",
 			obj: klcv1alpha2.Objective{
@@ -200,3 +266,78 @@ func TestCheckValue(t *testing.T) {
 
 	}
 }
+
+func TestComputeScore(t *testing.T) {
+	tests := []struct {
+		name          string
+		objectives    []klcv1alpha2.Objective
+		statuses      map[string]klcv1alpha2.EvaluationStatusItem
+		score         float64
+		keySLIsPassed bool
+		contributions map[string]string
+	}{
+		{
+			name: "all passed",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "a"},
+				{Name: "b", Weight: 3},
+			},
+			statuses: map[string]klcv1alpha2.EvaluationStatusItem{
+				"a": {Status: apicommon.StateSucceeded},
+				"b": {Status: apicommon.StateSucceeded},
+			},
+			score:         100,
+			keySLIsPassed: true,
+			contributions: map[string]string{"a": "1.00", "b": "3.00"},
+		},
+		{
+			name: "weighted with warning",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "a", Weight: 2},
+				{Name: "b", Weight: 1},
+				{Name: "c", Weight: 1},
+			},
+			statuses: map[string]klcv1alpha2.EvaluationStatusItem{
+				"a": {Status: apicommon.StateSucceeded},
+				"b": {Status: apicommon.StateWarning},
+				"c": {Status: apicommon.StateFailed},
+			},
+			score:         62.5,
+			keySLIsPassed: true,
+			contributions: map[string]string{"a": "2.00", "b": "0.50", "c": "0.00"},
+		},
+		{
+			name: "failed key SLI",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "a", Weight: 9},
+				{Name: "b", KeySLI: true},
+			},
+			statuses: map[string]klcv1alpha2.EvaluationStatusItem{
+				"a": {Status: apicommon.StateSucceeded},
+				"b": {Status: apicommon.StateWarning},
+			},
+			score:         95,
+			keySLIsPassed: false,
+			contributions: map[string]string{"a": "9.00", "b": "0.50"},
+		},
+		{
+			name:          "no objectives",
+			objectives:    []klcv1alpha2.Objective{},
+			statuses:      map[string]klcv1alpha2.EvaluationStatusItem{},
+			score:         0,
+			keySLIsPassed: true,
+			contributions: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, keySLIsPassed := computeScore(tt.objectives, tt.statuses)
+			require.Equal(t, tt.score, score)
+			require.Equal(t, tt.keySLIsPassed, keySLIsPassed)
+			for name, contribution := range tt.contributions {
+				require.Equal(t, contribution, tt.statuses[name].Score)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package keptnevaluation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluation/providers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fetchPreviousValue retrieves the value of a relative objective for the previous version.
+// The returned bool is false if there is no previous version or no value to compare to.
+func (r *KeptnEvaluationReconciler) fetchPreviousValue(ctx context.Context, provider providers.KeptnSLIProvider, evaluation *klcv1alpha2.KeptnEvaluation, objective klcv1alpha2.Objective, evaluationProvider klcv1alpha2.KeptnEvaluationProvider) (string, bool, error) {
+	if evaluation.Spec.PreviousVersion == "" {
+		return "", false, nil
+	}
+
+	if objective.Comparison.GetBaseline() == klcv1alpha2.ComparisonBaselineEvaluation {
+		return r.fetchPreviousEvaluationValue(ctx, evaluation, objective.Name)
+	}
+
+	previousQuery, err := getPreviousVersionQuery(objective, evaluation)
+	if err != nil {
+		return "", false, err
+	}
+	previousObjective := objective
+	previousObjective.Query = previousQuery
+	previousContext := newQueryContext(evaluation).forPreviousVersion()
+	value, _, err := queryValue(providers.ContextWithVariables(ctx, previousContext.variables()), provider, previousObjective, evaluationProvider)
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+func (r *KeptnEvaluationReconciler) fetchPreviousEvaluationValue(ctx context.Context, evaluation *klcv1alpha2.KeptnEvaluation, objectiveName string) (string, bool, error) {
+	evaluations := &klcv1alpha2.KeptnEvaluationList{}
+	if err := r.Client.List(ctx, evaluations, client.InNamespace(evaluation.Namespace)); err != nil {
+		return "", false, err
+	}
+
+	var previous *klcv1alpha2.KeptnEvaluation
+	for i := range evaluations.Items {
+		candidate := &evaluations.Items[i]
+		if !isPreviousEvaluation(evaluation, candidate) || !candidate.IsPassed() {
+			continue
+		}
+		if previous == nil || candidate.Status.EndTime.After(previous.Status.EndTime.Time) {
+			previous = candidate
+		}
+	}
+	if previous == nil {
+		return "", false, nil
+	}
+
+	item, ok := previous.Status.EvaluationStatus[objectiveName]
+	if !ok || item.Value == "" {
+		return "", false, nil
+	}
+	return item.Value, true, nil
+}
+
+// isPreviousEvaluation checks if the candidate is an evaluation of the same definition and phase for the previous version
+func isPreviousEvaluation(evaluation *klcv1alpha2.KeptnEvaluation, candidate *klcv1alpha2.KeptnEvaluation) bool {
+	return candidate.Spec.EvaluationDefinition == evaluation.Spec.EvaluationDefinition &&
+		candidate.Spec.Type == evaluation.Spec.Type &&
+		candidate.Spec.AppName == evaluation.Spec.AppName &&
+		candidate.Spec.Workload == evaluation.Spec.Workload &&
+		candidate.GetVersion() == evaluation.Spec.PreviousVersion
+}
+
+var versionVariablePattern = regexp.MustCompile(`{{[^}]*\.(WorkloadVersion|AppVersion)\b`)
+
+// ValidateComparison checks that the previous version of a relative objective can be queried:
+// the query baseline needs either a previousVersionQuery or a query using the version variables.
+func ValidateComparison(objective klcv1alpha2.Objective) error {
+	if objective.Comparison == nil || objective.Comparison.GetBaseline() != klcv1alpha2.ComparisonBaselineQuery {
+		return nil
+	}
+	if objective.Comparison.PreviousVersionQuery == "" && !versionVariablePattern.MatchString(objective.Query) {
+		return controllererrors.ErrComparisonQueryNotVersioned
+	}
+	return nil
+}
+
+// getPreviousVersionQuery returns the query scoped to the previous version, rendered with the context of the previous version
+func getPreviousVersionQuery(objective klcv1alpha2.Objective, evaluation *klcv1alpha2.KeptnEvaluation) (string, error) {
+	if err := ValidateComparison(objective); err != nil {
+		return "", err
+	}
+	previousContext := newQueryContext(evaluation).forPreviousVersion()
+	if objective.Comparison.PreviousVersionQuery != "" {
+		return renderQuery(objective.Comparison.PreviousVersionQuery, previousContext)
+	}
+	return renderQuery(objective.Query, previousContext)
+}
+
+// computeChange returns the change of the value compared to the previous value,
+// either in percent or as difference depending on the comparison type
+func computeChange(comparisonType klcv1alpha2.ComparisonType, value string, previousValue string) (float64, error) {
+	current, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse query result %q: %w", value, err)
+	}
+	previous, err := strconv.ParseFloat(previousValue, 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse value of the previous version %q: %w", previousValue, err)
+	}
+
+	difference := current - previous
+	if comparisonType == klcv1alpha2.ComparisonTypeAbsolute {
+		return difference, nil
+	}
+	if previous == 0 {
+		if difference == 0 {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("cannot compute the relative change to a previous value of 0")
+	}
+	return difference / math.Abs(previous) * 100, nil
+}
@@ -0,0 +1,207 @@
+package keptnevaluation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	apicommon "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2/common"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+type fakeSLIProvider struct {
+	queries []string
+	value   string
+	err     error
+}
+
+func (f *fakeSLIProvider) EvaluateQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	f.queries = append(f.queries, objective.Query)
+	return f.value, f.err
+}
+
+func TestComputeChange(t *testing.T) {
+	tests := []struct {
+		name           string
+		comparisonType klcv1alpha2.ComparisonType
+		value          string
+		previousValue  string
+		change         float64
+		err            bool
+	}{
+		{
+			name:           "relative increase",
+			comparisonType: klcv1alpha2.ComparisonTypeRelative,
+			value:          "110",
+			previousValue:  "100",
+			change:         10,
+		},
+		{
+			name:           "relative decrease of negative value",
+			comparisonType: klcv1alpha2.ComparisonTypeRelative,
+			value:          "-15",
+			previousValue:  "-10",
+			change:         -50,
+		},
+		{
+			name:           "absolute",
+			comparisonType: klcv1alpha2.ComparisonTypeAbsolute,
+			value:          "110",
+			previousValue:  "100",
+			change:         10,
+		},
+		{
+			name:           "relative to zero without change",
+			comparisonType: klcv1alpha2.ComparisonTypeRelative,
+			value:          "0",
+			previousValue:  "0",
+			change:         0,
+		},
+		{
+			name:           "relative to zero",
+			comparisonType: klcv1alpha2.ComparisonTypeRelative,
+			value:          "1",
+			previousValue:  "0",
+			err:            true,
+		},
+		{
+			name:           "garbage previous value",
+			comparisonType: klcv1alpha2.ComparisonTypeRelative,
+			value:          "1",
+			previousValue:  "garbage",
+			err:            true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, err := computeChange(tt.comparisonType, tt.value, tt.previousValue)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.InDelta(t, tt.change, change, 0.0001)
+		})
+	}
+}
+
+func TestGetPreviousVersionQuery(t *testing.T) {
+	evaluation := &klcv1alpha2.KeptnEvaluation{
+		Spec: klcv1alpha2.KeptnEvaluationSpec{
+			Workload:        "my-workload",
+			WorkloadVersion: "2.0.0",
+			PreviousVersion: "1.0.0",
+		},
+	}
+
+	// the version is not replaced in queries without the version variables
+	objective := klcv1alpha2.Objective{
+		Query:      `avg(latency{version="2.0.0"})`,
+		Comparison: &klcv1alpha2.ObjectiveComparison{},
+	}
+	_, err := getPreviousVersionQuery(objective, evaluation)
+	require.ErrorIs(t, err, controllererrors.ErrComparisonQueryNotVersioned)
+
+	objective.Query = `avg(latency{workload="{{.Workload}}",version="{{.WorkloadVersion}}"})`
+	query, err := getPreviousVersionQuery(objective, evaluation)
+	require.Nil(t, err)
+	require.Equal(t, `avg(latency{workload="my-workload",version="1.0.0"})`, query)
+
+	objective.Comparison.PreviousVersionQuery = "previous"
+	query, err = getPreviousVersionQuery(objective, evaluation)
+	require.Nil(t, err)
+	require.Equal(t, "previous", query)
+}
+
+func TestFetchPreviousValue_Query(t *testing.T) {
+	r := &KeptnEvaluationReconciler{
+		Log: ctrl.Log.WithName("testytest"),
+	}
+	provider := &fakeSLIProvider{value: "42"}
+	objective := klcv1alpha2.Objective{
+		Name:       "latency",
+		Query:      `latency{version="{{.WorkloadVersion}}"}`,
+		Comparison: &klcv1alpha2.ObjectiveComparison{},
+	}
+	evaluation := &klcv1alpha2.KeptnEvaluation{
+		Spec: klcv1alpha2.KeptnEvaluationSpec{
+			Workload:        "my-workload",
+			WorkloadVersion: "2.0.0",
+		},
+	}
+
+	// no previous version
+	_, found, err := r.fetchPreviousValue(context.TODO(), provider, evaluation, objective, klcv1alpha2.KeptnEvaluationProvider{})
+	require.Nil(t, err)
+	require.False(t, found)
+	require.Empty(t, provider.queries)
+
+	evaluation.Spec.PreviousVersion = "1.0.0"
+	value, found, err := r.fetchPreviousValue(context.TODO(), provider, evaluation, objective, klcv1alpha2.KeptnEvaluationProvider{})
+	require.Nil(t, err)
+	require.True(t, found)
+	require.Equal(t, "42", value)
+	require.Equal(t, []string{`latency{version="1.0.0"}`}, provider.queries)
+}
+
+func TestFetchPreviousValue_Evaluation(t *testing.T) {
+	previousEvaluation := func(name string, version string, state apicommon.KeptnState, endTime time.Time, value string) *klcv1alpha2.KeptnEvaluation {
+		return &klcv1alpha2.KeptnEvaluation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: klcv1alpha2.KeptnEvaluationSpec{
+				AppName:              "my-app",
+				Workload:             "my-workload",
+				WorkloadVersion:      version,
+				EvaluationDefinition: "my-definition",
+				Type:                 apicommon.PostDeploymentEvaluationCheckType,
+			},
+			Status: klcv1alpha2.KeptnEvaluationStatus{
+				OverallStatus: state,
+				EndTime:       metav1.NewTime(endTime),
+				EvaluationStatus: map[string]klcv1alpha2.EvaluationStatusItem{
+					"latency": {Value: value, Status: state},
+				},
+			},
+		}
+	}
+	now := time.Now()
+	fakeClient, err := fake.NewClient(
+		previousEvaluation("old", "1.0.0", apicommon.StateSucceeded, now.Add(-2*time.Hour), "10"),
+		previousEvaluation("latest", "1.0.0", apicommon.StateSucceeded, now.Add(-1*time.Hour), "20"),
+		previousEvaluation("failed", "1.0.0", apicommon.StateFailed, now, "30"),
+		previousEvaluation("other-version", "0.9.0", apicommon.StateSucceeded, now, "40"),
+	)
+	require.Nil(t, err)
+
+	r := &KeptnEvaluationReconciler{
+		Client: fakeClient,
+		Log:    ctrl.Log.WithName("testytest"),
+	}
+	evaluation := previousEvaluation("current", "2.0.0", apicommon.StateProgressing, time.Time{}, "")
+	evaluation.Spec.PreviousVersion = "1.0.0"
+	objective := klcv1alpha2.Objective{
+		Name: "latency",
+		Comparison: &klcv1alpha2.ObjectiveComparison{
+			Baseline: klcv1alpha2.ComparisonBaselineEvaluation,
+		},
+	}
+
+	value, found, err := r.fetchPreviousValue(context.TODO(), &fakeSLIProvider{}, evaluation, objective, klcv1alpha2.KeptnEvaluationProvider{})
+	require.Nil(t, err)
+	require.True(t, found)
+	require.Equal(t, "20", value)
+
+	objective.Name = "unknown"
+	_, found, err = r.fetchPreviousValue(context.TODO(), &fakeSLIProvider{}, evaluation, objective, klcv1alpha2.KeptnEvaluationProvider{})
+	require.Nil(t, err)
+	require.False(t, found)
+}
@@ -19,11 +19,13 @@ package keptnevaluation
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
 	apicommon "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2/common"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common"
 	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
 	providers "github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluation/providers"
 	"go.opentelemetry.io/otel"
@@ -32,7 +34,6 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -48,6 +49,10 @@ type KeptnEvaluationReconciler struct {
 	Log      logr.Logger
 	Meters   apicommon.KeptnMeters
 	Tracer   trace.Tracer
+	// ClusterProviderSecretNamespace is the namespace the secrets of ClusterKeptnEvaluationProviders are read from
+	ClusterProviderSecretNamespace string
+
+	queryLimiter queryLimiter
 }
 
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluations,verbs=get;list;watch;create;update;patch;delete
@@ -55,7 +60,12 @@ type KeptnEvaluationReconciler struct {
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluations/finalizers,verbs=update
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationproviders,verbs=get;list;watch
 //+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationdefinitions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=clusterkeptnevaluationproviders,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=clusterkeptnevaluationdefinitions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list
+//+kubebuilder:rbac:groups=metrics.k8s.io,resources=pods;nodes,verbs=get;list
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -103,12 +113,9 @@ func (r *KeptnEvaluationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, nil
 	}
 
-	if !evaluation.Status.OverallStatus.IsSucceeded() {
-		namespacedDefinition := types.NamespacedName{
-			Namespace: req.NamespacedName.Namespace,
-			Name:      evaluation.Spec.EvaluationDefinition,
-		}
-		evaluationDefinition, evaluationProvider, err := r.fetchDefinitionAndProvider(ctx, namespacedDefinition)
+	retryInterval := evaluation.Spec.RetryInterval.Duration
+	if !evaluation.IsPassed() {
+		evaluationDefinition, err := common.GetEvaluationDefinition(ctx, r.Client, evaluation.Spec.EvaluationDefinition, req.NamespacedName.Namespace)
 		if err != nil {
 			if errors.IsNotFound(err) {
 				r.Log.Info(err.Error() + ", ignoring error since object must be deleted")
@@ -119,17 +126,12 @@ func (r *KeptnEvaluationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			span.SetStatus(codes.Error, err.Error())
 			return ctrl.Result{}, nil
 		}
-		// load the provider
-		provider, err2 := providers.NewProvider(evaluationDefinition.Spec.Source, r.Log, r.Client)
-		if err2 != nil {
-			r.recordEvent("Error", evaluation, "ProviderNotFound", "evaluation provider was not found")
-			r.Log.Error(err2, "Failed to get the correct Metric Provider")
-			span.SetStatus(codes.Error, err2.Error())
-			return ctrl.Result{Requeue: false}, err2
+		// objectives are not queried before the initial delay and the observation window have passed,
+		// waiting does not count as a retry
+		if wait := time.Until(evaluationDefinition.GetEarliestQueryTime(evaluation.Status.StartTime.Time)); wait > 0 {
+			return r.delayEvaluation(ctx, evaluation, span, wait)
 		}
 
-		r.Log.Info("Metric Provider selected: " + evaluationDefinition.Spec.Source)
-
 		statusSummary := apicommon.StatusSummary{}
 		statusSummary.Total = len(evaluationDefinition.Spec.Objectives)
 		newStatus := make(map[string]klcv1alpha2.EvaluationStatusItem)
@@ -138,6 +140,8 @@ func (r *KeptnEvaluationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			evaluation.Status.EvaluationStatus = make(map[string]klcv1alpha2.EvaluationStatusItem)
 		}
 
+		var pendingObjectives []klcv1alpha2.Objective
+		usesDefinitionSource := false
 		for _, query := range evaluationDefinition.Spec.Objectives {
 			if _, ok := evaluation.Status.EvaluationStatus[query.Name]; !ok {
 				evaluation.AddEvaluationStatus(query)
@@ -147,32 +151,39 @@ func (r *KeptnEvaluationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 				newStatus[query.Name] = evaluation.Status.EvaluationStatus[query.Name]
 				continue
 			}
-			// resolving the SLI value
-			value, err := provider.EvaluateQuery(ctx, query, *evaluationProvider)
-			statusItem := &klcv1alpha2.EvaluationStatusItem{
-				Value:  value,
-				Status: apicommon.StateFailed,
-			}
-			if err != nil {
-				statusItem.Message = err.Error()
-				statusItem.Status = apicommon.StateFailed
-			}
-			// Evaluating SLO
-			check, err := checkValue(query, statusItem)
-			if err != nil {
-				statusItem.Message = err.Error()
-				r.Log.Error(err, "Could not check query result")
+			pendingObjectives = append(pendingObjectives, query)
+			usesDefinitionSource = usesDefinitionSource || query.Source == ""
+		}
+
+		// the provider of the definition is only loaded if a pending objective uses it,
+		// the providers of objectives overriding it are loaded on first use
+		providerCache := newProviderCache(r, evaluation)
+		if usesDefinitionSource {
+			resolved := providerCache.get(ctx, evaluationDefinition.Spec.Source)
+			if errors.IsNotFound(resolved.err) {
+				r.Log.Info(resolved.err.Error() + ", ignoring error since object must be deleted")
+				span.SetStatus(codes.Error, resolved.err.Error())
+				return ctrl.Result{Requeue: true, RequeueAfter: 10 * time.Second}, nil
 			}
-			if check {
-				statusItem.Status = apicommon.StateSucceeded
+			if resolved.err == nil {
+				r.Log.Info("Metric Provider selected: " + evaluationDefinition.Spec.Source + " of type " + resolved.evaluationProvider.GetType())
 			}
+		}
+
+		statusItems := r.evaluateObjectives(ctx, providerCache, evaluation, evaluationDefinition, pendingObjectives)
+		for i, query := range pendingObjectives {
+			statusItem := statusItems[i]
+			statusItem.RecordAttempt(evaluation.Status.EvaluationStatus[query.Name].History, time.Now())
 			statusSummary = apicommon.UpdateStatusSummary(statusItem.Status, statusSummary)
 			newStatus[query.Name] = *statusItem
 		}
 
 		evaluation.Status.RetryCount++
+		retryInterval = getRetryInterval(evaluationDefinition.Spec.Retry, evaluation.Spec.RetryInterval.Duration, evaluation.Status.RetryCount)
 		evaluation.Status.EvaluationStatus = newStatus
-		if apicommon.GetOverallState(statusSummary) == apicommon.StateSucceeded {
+		if evaluationDefinition.IsScoringEnabled() {
+			r.updateOverallStatusFromScore(evaluation, evaluationDefinition)
+		} else if apicommon.GetOverallState(statusSummary) == apicommon.StateSucceeded {
 			evaluation.Status.OverallStatus = apicommon.StateSucceeded
 		} else {
 			evaluation.Status.OverallStatus = apicommon.StateProgressing
@@ -180,7 +191,7 @@ func (r *KeptnEvaluationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 	}
 
-	if !evaluation.Status.OverallStatus.IsSucceeded() {
+	if !evaluation.IsPassed() {
 		// Evaluation is uncompleted, update status anyway this avoids updating twice in case of completion
 		err := r.Client.Status().Update(ctx, evaluation)
 		if err != nil {
@@ -191,7 +202,7 @@ func (r *KeptnEvaluationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 		r.recordEvent("Normal", evaluation, "NotFinished", "has not finished")
 
-		return ctrl.Result{Requeue: true, RequeueAfter: evaluation.Spec.RetryInterval.Duration}, nil
+		return ctrl.Result{Requeue: true, RequeueAfter: retryInterval}, nil
 
 	}
 
@@ -203,6 +214,182 @@ func (r *KeptnEvaluationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 }
 
+// evaluateObjectives queries the objectives concurrently and returns their status items in the same order.
+// Every objective is queried by its own provider, objectives of providers that could not be loaded
+// or are unhealthy fail without being queried.
+// Identical queries are sent only once and the number of concurrent queries is limited per provider.
+func (r *KeptnEvaluationReconciler) evaluateObjectives(ctx context.Context, providerCache *providerCache, evaluation *klcv1alpha2.KeptnEvaluation, evaluationDefinition *klcv1alpha2.KeptnEvaluationDefinition, objectives []klcv1alpha2.Objective) []*klcv1alpha2.EvaluationStatusItem {
+	statusItems := make([]*klcv1alpha2.EvaluationStatusItem, len(objectives))
+
+	var wg sync.WaitGroup
+	for i, objective := range objectives {
+		resolved := providerCache.get(ctx, evaluationDefinition.GetSource(objective))
+		if message := resolved.failureMessage(); message != "" {
+			statusItems[i] = &klcv1alpha2.EvaluationStatusItem{
+				Status:  apicommon.StateFailed,
+				Message: message,
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, objective klcv1alpha2.Objective) {
+			defer wg.Done()
+			objectiveCtx := ctx
+			if timeout := objective.GetTimeout(resolved.evaluationProvider); timeout > 0 {
+				var cancel context.CancelFunc
+				objectiveCtx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			statusItems[i] = r.evaluateObjective(objectiveCtx, resolved.queryProvider, evaluation, evaluationDefinition, objective, resolved.evaluationProvider)
+		}(i, objective)
+	}
+	wg.Wait()
+	return statusItems
+}
+
+func (r *KeptnEvaluationReconciler) evaluateObjective(ctx context.Context, provider providers.KeptnSLIProvider, evaluation *klcv1alpha2.KeptnEvaluation, evaluationDefinition *klcv1alpha2.KeptnEvaluationDefinition, objective klcv1alpha2.Objective, evaluationProvider klcv1alpha2.KeptnEvaluationProvider) *klcv1alpha2.EvaluationStatusItem {
+	statusItem := &klcv1alpha2.EvaluationStatusItem{
+		Status: apicommon.StateFailed,
+	}
+	objective.Range = resolveQueryRange(objective.Range, evaluationDefinition.GetObservationStart(evaluation.Status.StartTime.Time))
+	ctx = providers.ContextWithPhaseStart(ctx, evaluation.Status.StartTime.Time)
+	renderedObjective := objective
+	queryContext := newQueryContext(evaluation)
+	query, err := renderQuery(objective.Query, queryContext)
+	if err != nil {
+		statusItem.Message = err.Error()
+		return statusItem
+	}
+	renderedObjective.Query = query
+
+	// resolving the SLI value
+	queryCtx := providers.ContextWithVariables(ctx, queryContext.variables())
+	if objective.MultiSeries.IsPerSeries() {
+		return evaluatePerSeriesObjective(queryCtx, provider, renderedObjective, evaluationProvider, evaluationDefinition.IsScoringEnabled())
+	}
+	value, series, err := queryValue(queryCtx, provider, renderedObjective, evaluationProvider)
+	statusItem.Value = value
+	if len(series) > 0 {
+		statusItem.Series = getReducedSeriesStatus(objective, series, evaluationDefinition.IsScoringEnabled())
+	}
+	if err != nil {
+		statusItem.Message = err.Error()
+		return statusItem
+	}
+
+	if objective.Anomaly != nil {
+		if !r.checkAnomaly(queryCtx, provider, renderedObjective, evaluationProvider, statusItem) {
+			return statusItem
+		}
+	}
+
+	checkedItem := statusItem
+	if objective.Comparison != nil {
+		previousValue, found, err := r.fetchPreviousValue(ctx, provider, evaluation, objective, evaluationProvider)
+		if err != nil {
+			statusItem.Message = fmt.Sprintf("could not retrieve the value of the previous version: %s", err.Error())
+			return statusItem
+		}
+		if !found {
+			statusItem.Message = "no previous version to compare to"
+			statusItem.Status = apicommon.StateSucceeded
+			return statusItem
+		}
+		change, err := computeChange(objective.Comparison.GetType(), value, previousValue)
+		statusItem.PreviousValue = previousValue
+		if err != nil {
+			statusItem.Message = err.Error()
+			return statusItem
+		}
+		statusItem.Change = formatTargetNumber(change)
+		checkedItem = &klcv1alpha2.EvaluationStatusItem{Value: statusItem.Change}
+	}
+
+	// Evaluating SLO
+	check, err := checkValue(objective, checkedItem)
+	if err != nil {
+		statusItem.Message = err.Error()
+		r.Log.Error(err, "Could not check query result")
+	}
+	if check {
+		statusItem.Status = apicommon.StateSucceeded
+	} else if evaluationDefinition.IsScoringEnabled() && objective.WarningTarget != "" {
+		warning, err := checkTarget(objective.WarningTarget, checkedItem)
+		if err != nil {
+			statusItem.Message = err.Error()
+			r.Log.Error(err, "Could not check query result against warning target")
+		}
+		if warning {
+			statusItem.Status = apicommon.StateWarning
+		}
+	}
+	return statusItem
+}
+
+// checkAnomaly records the baseline of the objective and the deviation of the value from it in the status item.
+// It returns false if the objective failed because the value deviates too much or the baseline is not available.
+func (r *KeptnEvaluationReconciler) checkAnomaly(ctx context.Context, provider providers.KeptnSLIProvider, objective klcv1alpha2.Objective, evaluationProvider klcv1alpha2.KeptnEvaluationProvider, statusItem *klcv1alpha2.EvaluationStatusItem) bool {
+	baseline, err := fetchBaseline(ctx, provider, objective, evaluationProvider)
+	if err != nil {
+		statusItem.Message = fmt.Sprintf("could not retrieve the baseline: %s", err.Error())
+		return false
+	}
+	deviation, err := baseline.deviation(statusItem.Value)
+	statusItem.Baseline = formatTargetNumber(baseline.center)
+	if err != nil {
+		statusItem.Message = err.Error()
+		return false
+	}
+	statusItem.Deviation = formatTargetNumber(deviation)
+
+	target, err := getDeviationTarget(*objective.Anomaly)
+	if err != nil {
+		statusItem.Message = err.Error()
+		return false
+	}
+	normal, err := checkTarget(target, &klcv1alpha2.EvaluationStatusItem{Value: statusItem.Deviation})
+	if err != nil {
+		statusItem.Message = err.Error()
+		r.Log.Error(err, "Could not check deviation from the baseline")
+		return false
+	}
+	if !normal {
+		statusItem.Message = fmt.Sprintf("value %s deviates %s standard deviations from the baseline %s (standard deviation %s), allowed: %s",
+			statusItem.Value, statusItem.Deviation, statusItem.Baseline, formatTargetNumber(baseline.spread), target)
+	}
+	return normal
+}
+
+// delayEvaluation persists the start time of the evaluation and requeues it once the objectives can be queried
+func (r *KeptnEvaluationReconciler) delayEvaluation(ctx context.Context, evaluation *klcv1alpha2.KeptnEvaluation, span trace.Span, wait time.Duration) (ctrl.Result, error) {
+	message := fmt.Sprintf("waiting %s for the initial delay and observation window", wait.Round(time.Second))
+	r.Log.Info(message, "evaluation", evaluation.Name)
+	span.AddEvent(message)
+	if err := r.Client.Status().Update(ctx, evaluation); err != nil {
+		r.recordEvent("Warning", evaluation, "ReconcileErrored", "could not update status")
+		span.SetStatus(codes.Error, err.Error())
+		return ctrl.Result{Requeue: true}, err
+	}
+	r.recordEvent("Normal", evaluation, "Delayed", message)
+	return ctrl.Result{Requeue: true, RequeueAfter: wait}, nil
+}
+
+func (r *KeptnEvaluationReconciler) updateOverallStatusFromScore(evaluation *klcv1alpha2.KeptnEvaluation, evaluationDefinition *klcv1alpha2.KeptnEvaluationDefinition) {
+	score, keySLIsPassed := computeScore(evaluationDefinition.Spec.Objectives, evaluation.Status.EvaluationStatus)
+	evaluation.Status.Score = formatScore(score)
+	totalScore := evaluationDefinition.Spec.TotalScore
+
+	switch {
+	case keySLIsPassed && score >= float64(totalScore.PassPercentage):
+		evaluation.Status.OverallStatus = apicommon.StateSucceeded
+	case keySLIsPassed && totalScore.WarningPercentage > 0 && score >= float64(totalScore.WarningPercentage):
+		r.recordEvent("Warning", evaluation, "ScoreWarning", fmt.Sprintf("evaluation passed with warning, score %s%%", evaluation.Status.Score))
+		evaluation.Status.OverallStatus = apicommon.StateWarning
+	default:
+		evaluation.Status.OverallStatus = apicommon.StateProgressing
+	}
+}
+
 func (r *KeptnEvaluationReconciler) updateFinishedEvaluationMetrics(ctx context.Context, evaluation *klcv1alpha2.KeptnEvaluation, span trace.Span) error {
 	evaluation.SetEndTime()
 
@@ -233,22 +420,6 @@ func (r *KeptnEvaluationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Complete(r)
 }
 
-func (r *KeptnEvaluationReconciler) fetchDefinitionAndProvider(ctx context.Context, namespacedDefinition types.NamespacedName) (*klcv1alpha2.KeptnEvaluationDefinition, *klcv1alpha2.KeptnEvaluationProvider, error) {
-	evaluationDefinition := &klcv1alpha2.KeptnEvaluationDefinition{}
-	if err := r.Client.Get(ctx, namespacedDefinition, evaluationDefinition); err != nil {
-		return nil, nil, err
-	}
-	namespacedProvider := types.NamespacedName{
-		Namespace: namespacedDefinition.Namespace,
-		Name:      evaluationDefinition.Spec.Source,
-	}
-	evaluationProvider := &klcv1alpha2.KeptnEvaluationProvider{}
-	if err := r.Client.Get(ctx, namespacedProvider, evaluationProvider); err != nil {
-		return nil, nil, err
-	}
-	return evaluationDefinition, evaluationProvider, nil
-}
-
 func (r *KeptnEvaluationReconciler) recordEvent(eventType string, evaluation *klcv1alpha2.KeptnEvaluation, shortReason string, longReason string) {
 	r.Recorder.Event(evaluation, eventType, shortReason, fmt.Sprintf("%s / Namespace: %s, Name: %s, WorkloadVersion: %s ", longReason, evaluation.Namespace, evaluation.Name, evaluation.Spec.WorkloadVersion))
 }
@@ -0,0 +1,393 @@
+package keptnevaluation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	apicommon "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2/common"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestKeptnEvaluationReconciler_DelayedEvaluation(t *testing.T) {
+	evaluation := &klcv1alpha2.KeptnEvaluation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-evaluation",
+			Namespace: "default",
+		},
+		Spec: klcv1alpha2.KeptnEvaluationSpec{
+			EvaluationDefinition: "my-definition",
+			Retries:              10,
+		},
+	}
+	definition := &klcv1alpha2.KeptnEvaluationDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-definition",
+			Namespace: "default",
+		},
+		Spec: klcv1alpha2.KeptnEvaluationDefinitionSpec{
+			Source:            "prometheus",
+			InitialDelay:      metav1.Duration{Duration: 5 * time.Minute},
+			ObservationWindow: metav1.Duration{Duration: 10 * time.Minute},
+		},
+	}
+	provider := &klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prometheus",
+			Namespace: "default",
+		},
+	}
+	fakeClient, err := fake.NewClient(evaluation, definition, provider)
+	require.Nil(t, err)
+
+	r := &KeptnEvaluationReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(100),
+		Log:      ctrl.Log.WithName("testytest"),
+		Tracer:   trace.NewNoopTracerProvider().Tracer("tracer"),
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "my-evaluation"}}
+
+	result, err := r.Reconcile(context.TODO(), req)
+	require.Nil(t, err)
+	require.True(t, result.Requeue)
+	require.InDelta(t, (15 * time.Minute).Seconds(), result.RequeueAfter.Seconds(), 5)
+
+	updated := &klcv1alpha2.KeptnEvaluation{}
+	err = fakeClient.Get(context.TODO(), req.NamespacedName, updated)
+	require.Nil(t, err)
+	require.Equal(t, 0, updated.Status.RetryCount)
+	require.False(t, updated.Status.StartTime.IsZero())
+
+	// the persisted start time is used on the next reconcile
+	startTime := updated.Status.StartTime.Time
+	result, err = r.Reconcile(context.TODO(), req)
+	require.Nil(t, err)
+	require.InDelta(t, time.Until(startTime.Add(15*time.Minute)).Seconds(), result.RequeueAfter.Seconds(), 5)
+}
+
+func TestKeptnEvaluationReconciler_ObjectiveSources(t *testing.T) {
+	evaluation := &klcv1alpha2.KeptnEvaluation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-evaluation",
+			Namespace: "default",
+		},
+		Spec: klcv1alpha2.KeptnEvaluationSpec{
+			EvaluationDefinition: "my-definition",
+			Retries:              10,
+		},
+	}
+	definition := &klcv1alpha2.KeptnEvaluationDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-definition",
+			Namespace: "default",
+		},
+		Spec: klcv1alpha2.KeptnEvaluationDefinitionSpec{
+			Source: "default-static",
+			Objectives: []klcv1alpha2.Objective{
+				{
+					Name:             "default-source",
+					Query:            "5",
+					EvaluationTarget: "<10",
+				},
+				{
+					Name:             "other-source",
+					Source:           "other-static",
+					Query:            "15",
+					EvaluationTarget: "<10",
+				},
+				{
+					Name:             "missing-source",
+					Source:           "missing",
+					Query:            "5",
+					EvaluationTarget: "<10",
+				},
+			},
+		},
+	}
+	defaultProvider := &klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "default-static",
+			Namespace: "default",
+		},
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+			Type: "static",
+		},
+	}
+	otherProvider := &klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-static",
+			Namespace: "default",
+		},
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+			Type: "static",
+		},
+	}
+	fakeClient, err := fake.NewClient(evaluation, definition, defaultProvider, otherProvider)
+	require.Nil(t, err)
+
+	r := &KeptnEvaluationReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(100),
+		Log:      ctrl.Log.WithName("testytest"),
+		Tracer:   trace.NewNoopTracerProvider().Tracer("tracer"),
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "my-evaluation"}}
+
+	_, err = r.Reconcile(context.TODO(), req)
+	require.Nil(t, err)
+
+	updated := &klcv1alpha2.KeptnEvaluation{}
+	err = fakeClient.Get(context.TODO(), req.NamespacedName, updated)
+	require.Nil(t, err)
+
+	require.Equal(t, apicommon.StateSucceeded, updated.Status.EvaluationStatus["default-source"].Status)
+	require.Equal(t, "5", updated.Status.EvaluationStatus["default-source"].Value)
+	require.Equal(t, apicommon.StateFailed, updated.Status.EvaluationStatus["other-source"].Status)
+	require.Equal(t, "15", updated.Status.EvaluationStatus["other-source"].Value)
+	require.Equal(t, apicommon.StateFailed, updated.Status.EvaluationStatus["missing-source"].Status)
+	require.Contains(t, updated.Status.EvaluationStatus["missing-source"].Message, "could not get evaluation provider missing")
+}
+
+func TestKeptnEvaluationReconciler_UnusedDefinitionSource(t *testing.T) {
+	evaluation := &klcv1alpha2.KeptnEvaluation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-evaluation",
+			Namespace: "default",
+		},
+		Spec: klcv1alpha2.KeptnEvaluationSpec{
+			EvaluationDefinition: "my-definition",
+			Retries:              10,
+		},
+	}
+	definition := &klcv1alpha2.KeptnEvaluationDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-definition",
+			Namespace: "default",
+		},
+		Spec: klcv1alpha2.KeptnEvaluationDefinitionSpec{
+			// the source of the definition does not exist, but no objective uses it
+			Source: "missing",
+			Objectives: []klcv1alpha2.Objective{
+				{
+					Name:             "own-source",
+					Source:           "static",
+					Query:            "5",
+					EvaluationTarget: "<10",
+				},
+			},
+		},
+	}
+	provider := &klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "static",
+			Namespace: "default",
+		},
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+			Type: "static",
+		},
+	}
+	fakeClient, err := fake.NewClient(evaluation, definition, provider)
+	require.Nil(t, err)
+
+	r := &KeptnEvaluationReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(100),
+		Log:      ctrl.Log.WithName("testytest"),
+		Tracer:   trace.NewNoopTracerProvider().Tracer("tracer"),
+		Meters:   initEvaluationMeters(),
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "my-evaluation"}}
+
+	result, err := r.Reconcile(context.TODO(), req)
+	require.Nil(t, err)
+	require.Zero(t, result.RequeueAfter)
+
+	updated := &klcv1alpha2.KeptnEvaluation{}
+	err = fakeClient.Get(context.TODO(), req.NamespacedName, updated)
+	require.Nil(t, err)
+	require.Equal(t, apicommon.StateSucceeded, updated.Status.OverallStatus)
+	require.Equal(t, apicommon.StateSucceeded, updated.Status.EvaluationStatus["own-source"].Status)
+
+	// without a source, the objectives using the source of the definition fail
+	definition.Spec.Source = ""
+	definition.Spec.Objectives[0].Source = ""
+	err = fakeClient.Update(context.TODO(), definition)
+	require.Nil(t, err)
+	updated.Status = klcv1alpha2.KeptnEvaluationStatus{}
+	err = fakeClient.Status().Update(context.TODO(), updated)
+	require.Nil(t, err)
+
+	_, err = r.Reconcile(context.TODO(), req)
+	require.Nil(t, err)
+	err = fakeClient.Get(context.TODO(), req.NamespacedName, updated)
+	require.Nil(t, err)
+	require.Equal(t, apicommon.StateFailed, updated.Status.EvaluationStatus["own-source"].Status)
+	require.Contains(t, updated.Status.EvaluationStatus["own-source"].Message, controllererrors.ErrNoEvaluationProvider.Error())
+}
+
+func TestKeptnEvaluationReconciler_ClusterDefinitionAndProviders(t *testing.T) {
+	evaluation := &klcv1alpha2.KeptnEvaluation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-evaluation",
+			Namespace: "team-a",
+		},
+		Spec: klcv1alpha2.KeptnEvaluationSpec{
+			EvaluationDefinition: "shared-definition",
+			Retries:              10,
+		},
+	}
+	definition := &klcv1alpha2.ClusterKeptnEvaluationDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared-definition"},
+		Spec: klcv1alpha2.KeptnEvaluationDefinitionSpec{
+			Source: "shared-static",
+			Objectives: []klcv1alpha2.Objective{
+				{
+					Name:             "cluster-provider",
+					Query:            "5",
+					EvaluationTarget: "<10",
+				},
+				{
+					Name:             "restricted-provider",
+					Source:           "restricted-static",
+					Query:            "5",
+					EvaluationTarget: "<10",
+				},
+			},
+		},
+	}
+	provider := &klcv1alpha2.ClusterKeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared-static"},
+		Spec: klcv1alpha2.ClusterKeptnEvaluationProviderSpec{
+			KeptnEvaluationProviderSpec: klcv1alpha2.KeptnEvaluationProviderSpec{Type: "static"},
+		},
+	}
+	restrictedProvider := &klcv1alpha2.ClusterKeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted-static"},
+		Spec: klcv1alpha2.ClusterKeptnEvaluationProviderSpec{
+			KeptnEvaluationProviderSpec: klcv1alpha2.KeptnEvaluationProviderSpec{Type: "static"},
+			NamespaceSelector:           &metav1.LabelSelector{MatchLabels: map[string]string{"team": "platform"}},
+		},
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}}
+	fakeClient, err := fake.NewClient(evaluation, definition, provider, restrictedProvider, namespace)
+	require.Nil(t, err)
+
+	r := &KeptnEvaluationReconciler{
+		Client:                         fakeClient,
+		Recorder:                       record.NewFakeRecorder(100),
+		Log:                            ctrl.Log.WithName("testytest"),
+		Tracer:                         trace.NewNoopTracerProvider().Tracer("tracer"),
+		ClusterProviderSecretNamespace: "keptn-lifecycle-toolkit-system",
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "my-evaluation"}}
+
+	_, err = r.Reconcile(context.TODO(), req)
+	require.Nil(t, err)
+
+	updated := &klcv1alpha2.KeptnEvaluation{}
+	err = fakeClient.Get(context.TODO(), req.NamespacedName, updated)
+	require.Nil(t, err)
+
+	require.Equal(t, apicommon.StateSucceeded, updated.Status.EvaluationStatus["cluster-provider"].Status)
+	require.Equal(t, apicommon.StateFailed, updated.Status.EvaluationStatus["restricted-provider"].Status)
+	require.Contains(t, updated.Status.EvaluationStatus["restricted-provider"].Message, "namespaceSelector")
+}
+
+func TestKeptnEvaluationReconciler_Score(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		wantStatus  apicommon.KeptnState
+		wantScore   string
+		wantRequeue bool
+	}{
+		{name: "passed", value: "5", wantStatus: apicommon.StateSucceeded, wantScore: "100.00"},
+		{name: "warning threshold met", value: "15", wantStatus: apicommon.StateWarning, wantScore: "75.00"},
+		{name: "no threshold met", value: "25", wantStatus: apicommon.StateProgressing, wantScore: "50.00", wantRequeue: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluation := &klcv1alpha2.KeptnEvaluation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-evaluation",
+					Namespace: "default",
+				},
+				Spec: klcv1alpha2.KeptnEvaluationSpec{
+					EvaluationDefinition: "my-definition",
+					Retries:              10,
+				},
+			}
+			definition := &klcv1alpha2.KeptnEvaluationDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-definition",
+					Namespace: "default",
+				},
+				Spec: klcv1alpha2.KeptnEvaluationDefinitionSpec{
+					Source: "static",
+					Objectives: []klcv1alpha2.Objective{
+						{
+							Name:             "passing",
+							Query:            "5",
+							EvaluationTarget: "<10",
+						},
+						{
+							Name:             "checked",
+							Query:            tt.value,
+							EvaluationTarget: "<10",
+							WarningTarget:    "<20",
+						},
+					},
+					TotalScore: &klcv1alpha2.TotalScore{PassPercentage: 90, WarningPercentage: 70},
+				},
+			}
+			provider := &klcv1alpha2.KeptnEvaluationProvider{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "static",
+					Namespace: "default",
+				},
+			}
+			fakeClient, err := fake.NewClient(evaluation, definition, provider)
+			require.Nil(t, err)
+
+			r := &KeptnEvaluationReconciler{
+				Client:   fakeClient,
+				Recorder: record.NewFakeRecorder(100),
+				Log:      ctrl.Log.WithName("testytest"),
+				Meters:   initEvaluationMeters(),
+				Tracer:   trace.NewNoopTracerProvider().Tracer("tracer"),
+			}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "my-evaluation"}}
+
+			result, err := r.Reconcile(context.TODO(), req)
+			require.Nil(t, err)
+			require.Equal(t, tt.wantRequeue, result.Requeue)
+
+			updated := &klcv1alpha2.KeptnEvaluation{}
+			err = fakeClient.Get(context.TODO(), req.NamespacedName, updated)
+			require.Nil(t, err)
+			require.Equal(t, tt.wantStatus, updated.Status.OverallStatus)
+			require.Equal(t, tt.wantScore, updated.Status.Score)
+		})
+	}
+}
+
+func initEvaluationMeters() apicommon.KeptnMeters {
+	meter := metric.NewMeterProvider().Meter("keptn/evaluation")
+	evaluationCount, _ := meter.SyncInt64().Counter("keptn.evaluation.count")
+	evaluationDuration, _ := meter.SyncFloat64().Histogram("keptn.evaluation.duration")
+	return apicommon.KeptnMeters{
+		EvaluationCount:    evaluationCount,
+		EvaluationDuration: evaluationDuration,
+	}
+}
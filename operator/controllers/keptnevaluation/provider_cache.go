@@ -0,0 +1,92 @@
+package keptnevaluation
+
+import (
+	"context"
+	"fmt"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common"
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluation/providers"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// resolvedProvider is a provider used by the objectives of a single reconcile
+type resolvedProvider struct {
+	evaluationProvider klcv1alpha2.KeptnEvaluationProvider
+	// queryProvider deduplicates and limits the queries sent to the provider
+	queryProvider providers.KeptnSLIProvider
+	// err is set if the provider could not be loaded
+	err error
+	// unhealthyMessage is set if the provider is known to be unhealthy, it is not queried then
+	unhealthyMessage string
+}
+
+// failureMessage returns the reason why the objectives of the provider fail without being queried
+func (p *resolvedProvider) failureMessage() string {
+	if p.err != nil {
+		return p.err.Error()
+	}
+	return p.unhealthyMessage
+}
+
+// providerCache resolves the providers referenced by the objectives of a single reconcile,
+// every distinct provider is fetched and created only once
+type providerCache struct {
+	r          *KeptnEvaluationReconciler
+	evaluation *klcv1alpha2.KeptnEvaluation
+	providers  map[string]*resolvedProvider
+}
+
+func newProviderCache(r *KeptnEvaluationReconciler, evaluation *klcv1alpha2.KeptnEvaluation) *providerCache {
+	return &providerCache{
+		r:          r,
+		evaluation: evaluation,
+		providers:  make(map[string]*resolvedProvider),
+	}
+}
+
+// add creates the provider of the evaluation provider and caches it by its name
+func (c *providerCache) add(evaluationProvider *klcv1alpha2.KeptnEvaluationProvider) *resolvedProvider {
+	resolved := &resolvedProvider{evaluationProvider: *evaluationProvider}
+	c.providers[evaluationProvider.Name] = resolved
+
+	provider, err := providers.NewProvider(evaluationProvider.GetType(), c.r.Log, c.r.Client)
+	if err != nil {
+		resolved.err = err
+		return resolved
+	}
+	resolved.queryProvider = newDedupProvider(provider, c.r.queryLimiter.get(*evaluationProvider))
+
+	// providers known to be unhealthy are not queried, so that the objectives fail fast
+	if evaluationProvider.IsUnhealthy() {
+		resolved.unhealthyMessage = fmt.Sprintf("evaluation provider %s is unhealthy: %s", evaluationProvider.Name, evaluationProvider.Status.LastError)
+		c.r.recordEvent("Warning", c.evaluation, "ProviderUnhealthy", resolved.unhealthyMessage)
+	}
+	return resolved
+}
+
+// get returns the provider with the given name in the namespace of the evaluation, or the cluster provider
+// of the same name, loading it on first use
+func (c *providerCache) get(ctx context.Context, name string) *resolvedProvider {
+	if resolved, ok := c.providers[name]; ok {
+		return resolved
+	}
+	if name == "" {
+		resolved := &resolvedProvider{err: controllererrors.ErrNoEvaluationProvider}
+		c.providers[name] = resolved
+		return resolved
+	}
+	evaluationProvider, err := common.GetEvaluationProvider(ctx, c.r.Client, name, c.evaluation.Namespace, c.r.ClusterProviderSecretNamespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			c.r.recordEvent("Warning", c.evaluation, "ProviderNotFound", fmt.Sprintf("evaluation provider %s was not found", name))
+		} else {
+			c.r.recordEvent("Warning", c.evaluation, "ProviderUnavailable", err.Error())
+		}
+		resolved := &resolvedProvider{err: fmt.Errorf("could not get evaluation provider %s: %w", name, err)}
+		c.providers[name] = resolved
+		return resolved
+	}
+	return c.add(evaluationProvider)
+}
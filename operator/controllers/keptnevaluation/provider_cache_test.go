@@ -0,0 +1,49 @@
+package keptnevaluation
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestProviderCache_Get(t *testing.T) {
+	healthy := &klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "healthy", Namespace: "default"},
+		Spec:       klcv1alpha2.KeptnEvaluationProviderSpec{Type: "static"},
+	}
+	unsupported := &klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "unsupported", Namespace: "default"},
+		Spec:       klcv1alpha2.KeptnEvaluationProviderSpec{Type: "unknown"},
+	}
+	fakeClient, err := fake.NewClient(healthy, unsupported)
+	require.Nil(t, err)
+
+	r := &KeptnEvaluationReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(100),
+		Log:      ctrl.Log.WithName("testytest"),
+	}
+	evaluation := &klcv1alpha2.KeptnEvaluation{ObjectMeta: metav1.ObjectMeta{Name: "my-evaluation", Namespace: "default"}}
+	cache := newProviderCache(r, evaluation)
+
+	resolved := cache.get(context.TODO(), "healthy")
+	require.Empty(t, resolved.failureMessage())
+	require.NotNil(t, resolved.queryProvider)
+	require.Equal(t, "healthy", resolved.evaluationProvider.Name)
+	// every provider is created only once per reconcile
+	require.Same(t, resolved, cache.get(context.TODO(), "healthy"))
+
+	resolved = cache.get(context.TODO(), "unsupported")
+	require.NotNil(t, resolved.err)
+
+	resolved = cache.get(context.TODO(), "missing")
+	require.Contains(t, resolved.failureMessage(), "could not get evaluation provider missing")
+	require.Same(t, resolved, cache.get(context.TODO(), "missing"))
+	require.Len(t, cache.providers, 3)
+}
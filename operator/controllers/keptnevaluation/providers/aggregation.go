@@ -0,0 +1,59 @@
+package providers
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+)
+
+// aggregate reduces the values of a time series, ordered by time, to a single value
+func aggregate(values []float64, aggregation klcv1alpha2.RangeAggregation) (float64, error) {
+	if len(values) == 0 {
+		return 0, fmt.Errorf("no values in query result")
+	}
+
+	switch aggregation {
+	case klcv1alpha2.RangeAggregationAvg, "":
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values)), nil
+	case klcv1alpha2.RangeAggregationMax:
+		max := values[0]
+		for _, v := range values[1:] {
+			max = math.Max(max, v)
+		}
+		return max, nil
+	case klcv1alpha2.RangeAggregationMin:
+		min := values[0]
+		for _, v := range values[1:] {
+			min = math.Min(min, v)
+		}
+		return min, nil
+	case klcv1alpha2.RangeAggregationP90:
+		return percentile(values, 90), nil
+	case klcv1alpha2.RangeAggregationP95:
+		return percentile(values, 95), nil
+	case klcv1alpha2.RangeAggregationP99:
+		return percentile(values, 99), nil
+	case klcv1alpha2.RangeAggregationLast:
+		return values[len(values)-1], nil
+	default:
+		return 0, fmt.Errorf("aggregation %s not supported", aggregation)
+	}
+}
+
+// percentile returns the p-th percentile of the values, interpolating linearly between the closest ranks
+func percentile(values []float64, p float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
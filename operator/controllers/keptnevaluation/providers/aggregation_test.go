@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"testing"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregate(t *testing.T) {
+	values := []float64{4, 1, 3, 2, 10, 6, 7, 8, 9, 5}
+	tests := []struct {
+		aggregation klcv1alpha2.RangeAggregation
+		values      []float64
+		result      float64
+		err         bool
+	}{
+		{aggregation: klcv1alpha2.RangeAggregationAvg, values: values, result: 5.5},
+		{aggregation: "", values: values, result: 5.5},
+		{aggregation: klcv1alpha2.RangeAggregationMax, values: values, result: 10},
+		{aggregation: klcv1alpha2.RangeAggregationMin, values: values, result: 1},
+		{aggregation: klcv1alpha2.RangeAggregationP90, values: values, result: 9.1},
+		{aggregation: klcv1alpha2.RangeAggregationP95, values: values, result: 9.55},
+		{aggregation: klcv1alpha2.RangeAggregationP99, values: values, result: 9.91},
+		{aggregation: klcv1alpha2.RangeAggregationP99, values: []float64{42}, result: 42},
+		{aggregation: klcv1alpha2.RangeAggregationLast, values: values, result: 5},
+		{aggregation: klcv1alpha2.RangeAggregationAvg, values: []float64{}, err: true},
+		{aggregation: "median", values: values, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.aggregation), func(t *testing.T) {
+			r, err := aggregate(tt.values, tt.aggregation)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.InDelta(t, tt.result, r, 0.0001)
+		})
+	}
+	// the values must not be reordered by percentiles
+	require.Equal(t, float64(4), values[0])
+}
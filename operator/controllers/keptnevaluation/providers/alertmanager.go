@@ -0,0 +1,158 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KeptnAlertmanagerProvider evaluates objectives by counting the active alerts of a Prometheus Alertmanager
+// that match the label matchers of the query, e.g. to block deployments while an incident is ongoing
+type KeptnAlertmanagerProvider struct {
+	Log        logr.Logger
+	httpClient http.Client
+	k8sClient  client.Client
+}
+
+type alertmanagerAlert struct {
+	Labels map[string]string `json:"labels"`
+	Status struct {
+		State string `json:"state"`
+	} `json:"status"`
+}
+
+// EvaluateQuery returns the number of active alerts matching the query, silenced and inhibited alerts are
+// only counted if the objective includes them
+func (a *KeptnAlertmanagerProvider) EvaluateQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	params, err := getAlertmanagerQueryParams(objective)
+	if err != nil {
+		return "", err
+	}
+	qURL := strings.TrimSuffix(provider.Spec.TargetServer, "/") + "/api/v2/alerts?" + params.Encode()
+	a.Log.Info("Running query: " + qURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, qURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	httpClient, err := newProviderHTTPClient(ctx, a.k8sClient, a.httpClient, provider)
+	if err != nil {
+		return "", err
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		a.Log.Error(err, "Error while sending request")
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return "", fmt.Errorf("request failed with status code %d", res.StatusCode)
+	}
+
+	var alerts []alertmanagerAlert
+	if err := json.NewDecoder(res.Body).Decode(&alerts); err != nil {
+		a.Log.Error(err, "Error while parsing response")
+		return "", err
+	}
+	for _, alert := range alerts {
+		a.Log.Info("Matching alert", "alertname", alert.Labels["alertname"], "state", alert.Status.State)
+	}
+	return strconv.Itoa(len(alerts)), nil
+}
+
+// getAlertmanagerQueryParams returns the filter of the v2 alerts API for the label matchers of the query
+func getAlertmanagerQueryParams(objective klcv1alpha2.Objective) (url.Values, error) {
+	params := url.Values{}
+	params.Set("active", "true")
+	params.Set("silenced", "false")
+	params.Set("inhibited", "false")
+	if query := objective.Alertmanager; query != nil {
+		params.Set("silenced", strconv.FormatBool(query.IncludeSilenced))
+		params.Set("inhibited", strconv.FormatBool(query.IncludeInhibited))
+		if query.Receiver != "" {
+			params.Set("receiver", query.Receiver)
+		}
+	}
+	matchers, err := parseAlertMatchers(objective.Query)
+	if err != nil {
+		return nil, err
+	}
+	for _, matcher := range matchers {
+		params.Add("filter", matcher)
+	}
+	return params, nil
+}
+
+// parseAlertMatchers splits label matchers such as {severity="critical",app=~"checkout.*"} into single matchers.
+// An empty query matches every alert.
+func parseAlertMatchers(query string) ([]string, error) {
+	query = strings.TrimSpace(query)
+	if strings.HasPrefix(query, "{") && strings.HasSuffix(query, "}") {
+		query = query[1 : len(query)-1]
+	}
+
+	var matchers []string
+	var current strings.Builder
+	inQuotes, escaped := false, false
+	for _, r := range query {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && inQuotes:
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ',' && !inQuotes:
+			matchers = append(matchers, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in label matchers %s", query)
+	}
+	matchers = append(matchers, current.String())
+
+	result := make([]string, 0, len(matchers))
+	for _, matcher := range matchers {
+		matcher = strings.TrimSpace(matcher)
+		if matcher == "" {
+			continue
+		}
+		if !strings.ContainsAny(matcher, "=~") {
+			return nil, fmt.Errorf("invalid label matcher %s", matcher)
+		}
+		result = append(result, matcher)
+	}
+	return result, nil
+}
+
+// CheckHealth calls the health endpoint of the Alertmanager with the configured credentials
+func (a *KeptnAlertmanagerProvider) CheckHealth(ctx context.Context, provider klcv1alpha2.KeptnEvaluationProvider) error {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(provider.Spec.TargetServer, "/")+"/-/healthy", nil)
+	if err != nil {
+		return err
+	}
+	httpClient, err := newProviderHTTPClient(ctx, a.k8sClient, a.httpClient, provider)
+	if err != nil {
+		return err
+	}
+	return checkHealthResponse(httpClient.Do(req))
+}
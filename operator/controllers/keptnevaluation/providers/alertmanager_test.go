@@ -0,0 +1,155 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const alertmanagerPayload = `[
+	{"labels":{"alertname":"HighErrorRate","severity":"critical","app":"checkout"},"status":{"state":"active"}},
+	{"labels":{"alertname":"PodCrashLooping","severity":"critical","app":"checkout"},"status":{"state":"active"}}
+]`
+
+func TestAlertmanagerProvider(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v2/alerts", r.URL.Path)
+		require.Equal(t, "Bearer secretValue", r.Header.Get("Authorization"))
+		query := r.URL.Query()
+		require.Equal(t, "true", query.Get("active"))
+		require.Equal(t, "false", query.Get("silenced"))
+		require.Equal(t, "false", query.Get("inhibited"))
+		require.Equal(t, []string{`severity="critical"`, `app=~"checkout|cart"`}, query["filter"])
+		_, err := w.Write([]byte(alertmanagerPayload))
+		require.Nil(t, err)
+	}))
+	defer svr.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "alertmanager", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("secretValue")},
+	}
+	fakeClient, err := fake.NewClient(secret)
+	require.Nil(t, err)
+
+	kap := KeptnAlertmanagerProvider{
+		httpClient: http.Client{},
+		Log:        ctrl.Log.WithName("testytest"),
+		k8sClient:  fakeClient,
+	}
+	provider := klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "alertmanager", Namespace: "default"},
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+			TargetServer: svr.URL + "/",
+			SecretKeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "alertmanager"},
+				Key:                  "token",
+			},
+		},
+	}
+	objective := klcv1alpha2.Objective{
+		Query:            `{severity="critical", app=~"checkout|cart"}`,
+		EvaluationTarget: "==0",
+	}
+
+	r, err := kap.EvaluateQuery(context.TODO(), objective, provider)
+	require.Nil(t, err)
+	require.Equal(t, "2", r)
+}
+
+func TestAlertmanagerProvider_Errors(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer svr.Close()
+
+	kap := KeptnAlertmanagerProvider{
+		httpClient: http.Client{},
+		Log:        ctrl.Log.WithName("testytest"),
+	}
+	provider := klcv1alpha2.KeptnEvaluationProvider{
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{TargetServer: svr.URL},
+	}
+
+	_, err := kap.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: `severity="critical"`}, provider)
+	require.ErrorContains(t, err, "request failed with status code 400")
+
+	_, err = kap.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: `severity`}, provider)
+	require.ErrorContains(t, err, "invalid label matcher severity")
+
+	require.NotNil(t, kap.CheckHealth(context.TODO(), provider))
+}
+
+func TestGetAlertmanagerQueryParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		objective klcv1alpha2.Objective
+		want      map[string][]string
+		err       bool
+	}{
+		{
+			name:      "every alert",
+			objective: klcv1alpha2.Objective{Query: ""},
+			want:      map[string][]string{"active": {"true"}, "silenced": {"false"}, "inhibited": {"false"}},
+		},
+		{
+			name:      "matchers without braces",
+			objective: klcv1alpha2.Objective{Query: `alertname!="Watchdog",severity=~"critical|page"`},
+			want: map[string][]string{
+				"active":    {"true"},
+				"silenced":  {"false"},
+				"inhibited": {"false"},
+				"filter":    {`alertname!="Watchdog"`, `severity=~"critical|page"`},
+			},
+		},
+		{
+			name:      "comma in quotes",
+			objective: klcv1alpha2.Objective{Query: `{team="a,b",summary="say \"hi\", please"}`},
+			want: map[string][]string{
+				"active":    {"true"},
+				"silenced":  {"false"},
+				"inhibited": {"false"},
+				"filter":    {`team="a,b"`, `summary="say \"hi\", please"`},
+			},
+		},
+		{
+			name: "silenced, inhibited and receiver",
+			objective: klcv1alpha2.Objective{
+				Query:        `severity="critical"`,
+				Alertmanager: &klcv1alpha2.AlertmanagerQuery{Receiver: "oncall.*", IncludeSilenced: true, IncludeInhibited: true},
+			},
+			want: map[string][]string{
+				"active":    {"true"},
+				"silenced":  {"true"},
+				"inhibited": {"true"},
+				"receiver":  {"oncall.*"},
+				"filter":    {`severity="critical"`},
+			},
+		},
+		{
+			name:      "unterminated quote",
+			objective: klcv1alpha2.Objective{Query: `severity="critical`},
+			err:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := getAlertmanagerQueryParams(tt.objective)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, map[string][]string(params))
+		})
+	}
+}
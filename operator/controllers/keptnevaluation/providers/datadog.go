@@ -0,0 +1,186 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DatadogAPIKey is the key of the Datadog API key in the secret of the provider
+	DatadogAPIKey = "DD_API_KEY"
+	// DatadogAppKey is the key of the Datadog application key in the secret of the provider
+	DatadogAppKey = "DD_APPLICATION_KEY"
+)
+
+type KeptnDatadogProvider struct {
+	Log        logr.Logger
+	httpClient http.Client
+	k8sClient  client.Client
+}
+
+type DatadogResponse struct {
+	Status string          `json:"status"`
+	Error  string          `json:"error,omitempty"`
+	Series []DatadogSeries `json:"series"`
+}
+
+type DatadogSeries struct {
+	Metric    string       `json:"metric"`
+	Scope     string       `json:"scope"`
+	Pointlist [][]*float64 `json:"pointlist"`
+}
+
+func (d *KeptnDatadogProvider) EvaluateQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	to := time.Now()
+	from := to.Add(-objective.GetRangeInterval())
+	params := url.Values{}
+	params.Set("from", strconv.FormatInt(from.Unix(), 10))
+	params.Set("to", strconv.FormatInt(to.Unix(), 10))
+	params.Set("query", objective.Query)
+	qURL := provider.Spec.TargetServer + "/api/v1/query?" + params.Encode()
+
+	d.Log.Info("Running query: " + qURL)
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", qURL, nil)
+	if err != nil {
+		d.Log.Error(err, "Error while creating request")
+		return "", err
+	}
+
+	apiKey, appKey, err := d.getDatadogKeys(ctx, provider)
+	if err != nil {
+		return "", err
+	}
+
+	httpClient, err := newProviderTransportClient(ctx, d.k8sClient, d.httpClient, provider)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("DD-API-KEY", apiKey)
+	req.Header.Set("DD-APPLICATION-KEY", appKey)
+	req.Header.Set("Accept", "application/json")
+	res, err := httpClient.Do(req)
+	if err != nil {
+		d.Log.Error(err, "Error while sending request")
+		return "", err
+	}
+	defer func() {
+		err := res.Body.Close()
+		if err != nil {
+			d.Log.Error(err, "Could not close request body")
+		}
+	}()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	// error pages of proxies are not JSON, so the status code is checked before the body is parsed
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return "", fmt.Errorf("query failed with status code %d: %s", res.StatusCode, string(b))
+	}
+	result := DatadogResponse{}
+	err = json.Unmarshal(b, &result)
+	if err != nil {
+		d.Log.Error(err, "Error while parsing response")
+		return "", err
+	}
+	if result.Status == "error" {
+		return "", fmt.Errorf("query failed: %s", result.Error)
+	}
+
+	value, err := d.getSingleValue(result, objective.GetRangeAggregation())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%f", value), nil
+}
+
+// getSingleValue reduces the data points of all series in the response, ordered by time,
+// to a single value with the aggregation of the objective
+func (d *KeptnDatadogProvider) getSingleValue(result DatadogResponse, aggregation klcv1alpha2.RangeAggregation) (float64, error) {
+	type point struct {
+		timestamp float64
+		value     float64
+	}
+	points := []point{}
+	for _, series := range result.Series {
+		for _, p := range series.Pointlist {
+			// a point consists of the timestamp and the value
+			if len(p) < 2 || p[0] == nil || p[1] == nil {
+				continue
+			}
+			points = append(points, point{timestamp: *p[0], value: *p[1]})
+		}
+	}
+	if len(points) == 0 {
+		return 0, errors.New("no data points returned by the query")
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].timestamp < points[j].timestamp
+	})
+	values := make([]float64, 0, len(points))
+	for _, p := range points {
+		values = append(values, p.value)
+	}
+	return aggregate(values, aggregation)
+}
+
+// getDatadogKeys reads the API and application keys from the secret referenced by the provider.
+// Only the name of the secret is used, the keys are always read from DD_API_KEY and DD_APPLICATION_KEY.
+func (d *KeptnDatadogProvider) getDatadogKeys(ctx context.Context, provider klcv1alpha2.KeptnEvaluationProvider) (string, string, error) {
+	if provider.Spec.SecretKeyRef.Name == "" {
+		return "", "", errors.New("the SecretKeyRef property with the Datadog API and application keys is missing")
+	}
+	ddCredsSecret := &corev1.Secret{}
+	if err := d.k8sClient.Get(ctx, types.NamespacedName{Name: provider.Spec.SecretKeyRef.Name, Namespace: provider.Namespace}, ddCredsSecret); err != nil {
+		return "", "", err
+	}
+
+	apiKey := ddCredsSecret.Data[DatadogAPIKey]
+	if len(apiKey) == 0 {
+		return "", "", fmt.Errorf("secret does not contain the key %s", DatadogAPIKey)
+	}
+	appKey := ddCredsSecret.Data[DatadogAppKey]
+	if len(appKey) == 0 {
+		return "", "", fmt.Errorf("secret does not contain the key %s", DatadogAppKey)
+	}
+	return string(apiKey), string(appKey), nil
+}
+
+// CheckHealth validates the API key to check that the target server is reachable with the configured keys
+func (d *KeptnDatadogProvider) CheckHealth(ctx context.Context, provider klcv1alpha2.KeptnEvaluationProvider) error {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", provider.Spec.TargetServer+"/api/v1/validate", nil)
+	if err != nil {
+		return err
+	}
+	apiKey, appKey, err := d.getDatadogKeys(ctx, provider)
+	if err != nil {
+		return err
+	}
+	httpClient, err := newProviderTransportClient(ctx, d.k8sClient, d.httpClient, provider)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("DD-API-KEY", apiKey)
+	req.Header.Set("DD-APPLICATION-KEY", appKey)
+	return checkHealthResponse(httpClient.Do(req))
+}
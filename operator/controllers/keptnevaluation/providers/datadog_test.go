@@ -0,0 +1,222 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const ddpayload = `{"status":"ok","res_type":"time_series","series":[{"metric":"system.cpu.idle","scope":"host:a","pointlist":[[1666090140000,10.0],[1666090200000,null],[1666090260000,20.0]]},{"metric":"system.cpu.idle","scope":"host:b","pointlist":[[1666090140000,30.0]]}]}`
+
+func datadogTestSetup(t *testing.T, serverURL string, secretData map[string][]byte) (*KeptnDatadogProvider, klcv1alpha2.KeptnEvaluationProvider) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "datadog-keys",
+			Namespace: "",
+		},
+		Data: secretData,
+	}
+	fakeClient, err := fake.NewClient(secret)
+	require.Nil(t, err)
+
+	kdp := &KeptnDatadogProvider{
+		httpClient: http.Client{},
+		Log:        ctrl.Log.WithName("testytest"),
+		k8sClient:  fakeClient,
+	}
+	p := klcv1alpha2.KeptnEvaluationProvider{
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+			SecretKeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "datadog-keys",
+				},
+			},
+			TargetServer: serverURL,
+		},
+	}
+	return kdp, p
+}
+
+func TestDatadogProvider_HappyPath(t *testing.T) {
+	const query = "avg:system.cpu.idle{*}"
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "GET", r.Method)
+		require.Equal(t, "/api/v1/query", r.URL.Path)
+		require.Equal(t, query, r.URL.Query().Get("query"))
+		require.Equal(t, "apikey", r.Header.Get("DD-API-KEY"))
+		require.Equal(t, "appkey", r.Header.Get("DD-APPLICATION-KEY"))
+
+		from, err := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+		require.Nil(t, err)
+		to, err := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+		require.Nil(t, err)
+		require.Equal(t, int64(10*60), to-from)
+
+		_, err = w.Write([]byte(ddpayload))
+		require.Nil(t, err)
+	}))
+	defer svr.Close()
+
+	kdp, p := datadogTestSetup(t, svr.URL, map[string][]byte{
+		DatadogAPIKey: []byte("apikey"),
+		DatadogAppKey: []byte("appkey"),
+	})
+	obj := klcv1alpha2.Objective{
+		Query: query,
+		Range: &klcv1alpha2.QueryRange{
+			Interval: metav1.Duration{Duration: 10 * time.Minute},
+		},
+	}
+	r, e := kdp.EvaluateQuery(context.TODO(), obj, p)
+	require.Nil(t, e)
+	require.Equal(t, "20.000000", r)
+}
+
+func TestDatadogProvider_Aggregation(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(ddpayload))
+		require.Nil(t, err)
+	}))
+	defer svr.Close()
+
+	kdp, p := datadogTestSetup(t, svr.URL, map[string][]byte{
+		DatadogAPIKey: []byte("apikey"),
+		DatadogAppKey: []byte("appkey"),
+	})
+	tests := []struct {
+		aggregation klcv1alpha2.RangeAggregation
+		result      string
+	}{
+		{aggregation: klcv1alpha2.RangeAggregationAvg, result: "20.000000"},
+		{aggregation: klcv1alpha2.RangeAggregationMax, result: "30.000000"},
+		{aggregation: klcv1alpha2.RangeAggregationMin, result: "10.000000"},
+		// the newest data point of all series
+		{aggregation: klcv1alpha2.RangeAggregationLast, result: "20.000000"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.aggregation), func(t *testing.T) {
+			obj := klcv1alpha2.Objective{
+				Query: "avg:system.cpu.idle{*}",
+				Range: &klcv1alpha2.QueryRange{Aggregation: tt.aggregation},
+			}
+			r, e := kdp.EvaluateQuery(context.TODO(), obj, p)
+			require.Nil(t, e)
+			require.Equal(t, tt.result, r)
+		})
+	}
+}
+
+func TestDatadogProvider_Errors(t *testing.T) {
+	validKeys := map[string][]byte{
+		DatadogAPIKey: []byte("apikey"),
+		DatadogAppKey: []byte("appkey"),
+	}
+	tests := []struct {
+		name       string
+		statusCode int
+		payload    string
+		secretData map[string][]byte
+	}{
+		{
+			name:       "missing application key",
+			statusCode: http.StatusOK,
+			payload:    ddpayload,
+			secretData: map[string][]byte{DatadogAPIKey: []byte("apikey")},
+		},
+		{
+			name:       "forbidden",
+			statusCode: http.StatusForbidden,
+			payload:    `{"errors":["Forbidden"]}`,
+			secretData: validKeys,
+		},
+		{
+			name:       "query error",
+			statusCode: http.StatusOK,
+			payload:    `{"status":"error","error":"Error parsing query"}`,
+			secretData: validKeys,
+		},
+		{
+			name:       "no data points",
+			statusCode: http.StatusOK,
+			payload:    `{"status":"ok","series":[{"metric":"system.cpu.idle","pointlist":[[1666090140000,null]]}]}`,
+			secretData: validKeys,
+		},
+		{
+			name:       "garbage",
+			statusCode: http.StatusOK,
+			payload:    "garbage",
+			secretData: validKeys,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, err := w.Write([]byte(tt.payload))
+				require.Nil(t, err)
+			}))
+			defer svr.Close()
+
+			kdp, p := datadogTestSetup(t, svr.URL, tt.secretData)
+			r, e := kdp.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: "avg:system.cpu.idle{*}"}, p)
+			require.Equal(t, "", r)
+			require.NotNil(t, e)
+		})
+	}
+}
+
+func TestDatadogProvider_ErrorPage(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, err := w.Write([]byte("<html>Bad Gateway</html>"))
+		require.Nil(t, err)
+	}))
+	defer svr.Close()
+
+	kdp, p := datadogTestSetup(t, svr.URL, map[string][]byte{
+		DatadogAPIKey: []byte("apikey"),
+		DatadogAppKey: []byte("appkey"),
+	})
+	_, e := kdp.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: "avg:system.cpu.idle{*}"}, p)
+	require.NotNil(t, e)
+	require.Contains(t, e.Error(), "status code 502")
+	require.Contains(t, e.Error(), "<html>Bad Gateway</html>")
+}
+
+func TestDatadogProvider_Headers(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "tenant-a", r.Header.Get("X-Tenant"))
+		require.Equal(t, "apikey", r.Header.Get("DD-API-KEY"))
+		_, err := w.Write([]byte(ddpayload))
+		require.Nil(t, err)
+	}))
+	defer svr.Close()
+
+	kdp, p := datadogTestSetup(t, svr.URL, map[string][]byte{
+		DatadogAPIKey: []byte("apikey"),
+		DatadogAppKey: []byte("appkey"),
+	})
+	p.Spec.Headers = map[string]string{"X-Tenant": "tenant-a"}
+	_, e := kdp.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: "avg:system.cpu.idle{*}"}, p)
+	require.Nil(t, e)
+	require.Nil(t, kdp.CheckHealth(context.TODO(), p))
+}
+
+func TestDatadogProvider_MissingSecret(t *testing.T) {
+	kdp, p := datadogTestSetup(t, "http://localhost", nil)
+	p.Spec.SecretKeyRef.Name = ""
+	r, e := kdp.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: "avg:system.cpu.idle{*}"}, p)
+	require.Equal(t, "", r)
+	require.NotNil(t, e)
+}
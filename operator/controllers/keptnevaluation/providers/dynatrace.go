@@ -7,25 +7,65 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// DynatraceDefaultTokenURL is the token URL of the Dynatrace SSO used for OAuth client credentials
+	DynatraceDefaultTokenURL = "https://sso.dynatrace.com/sso/oauth2/token"
+)
+
 type KeptnDynatraceProvider struct {
 	Log        logr.Logger
 	httpClient http.Client
 	k8sClient  client.Client
 }
 
+// oauthTokens keeps the OAuth access tokens until they expire. The provider implementations are created
+// for every evaluation and health check, so the tokens are cached per KeptnEvaluationProvider and its generation.
+var oauthTokens = &oauthTokenCache{tokens: map[string]*oauth2.Token{}}
+
+type oauthTokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]*oauth2.Token
+}
+
+func (c *oauthTokenCache) get(key string) (*oauth2.Token, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	token, ok := c.tokens[key]
+	return token, ok && token.Valid()
+}
+
+// set stores the token and drops the expired ones, so that tokens of changed or deleted providers do not pile up
+func (c *oauthTokenCache) set(key string, token *oauth2.Token) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, t := range c.tokens {
+		if !t.Valid() {
+			delete(c.tokens, k)
+		}
+	}
+	c.tokens[key] = token
+}
+
 type DynatraceResponse struct {
 	TotalCount int               `json:"totalCount"`
 	Resolution string            `json:"resolution"`
 	Result     []DynatraceResult `json:"result"`
+	Error      *DynatraceError   `json:"error,omitempty"`
 }
 
 type DynatraceResult struct {
@@ -38,11 +78,24 @@ type DynatraceData struct {
 	Values     []*float64 `json:"values"`
 }
 
+type DynatraceError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
 func (d *KeptnDynatraceProvider) EvaluateQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
-	qURL := provider.Spec.TargetServer + "/api/v2/metrics/query?metricSelector=" + objective.Query
+	query := klcv1alpha2.DynatraceQuery{}
+	if objective.Dynatrace != nil {
+		query = *objective.Dynatrace
+	}
+	params, err := getDynatraceQueryParams(ctx, objective, query)
+	if err != nil {
+		return "", err
+	}
+	qURL := provider.Spec.TargetServer + "/api/v2/metrics/query?" + params.Encode()
 
 	d.Log.Info("Running query: " + qURL)
-	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	ctx, cancel := withQueryTimeout(ctx)
 	defer cancel()
 	req, err := http.NewRequestWithContext(ctx, "GET", qURL, nil)
 	if err != nil {
@@ -50,13 +103,14 @@ func (d *KeptnDynatraceProvider) EvaluateQuery(ctx context.Context, objective kl
 		return "", err
 	}
 
-	token, err := d.getDTApiToken(ctx, provider)
+	if err := d.authorize(ctx, req, provider); err != nil {
+		return "", err
+	}
+	httpClient, err := newProviderTransportClient(ctx, d.k8sClient, d.httpClient, provider)
 	if err != nil {
 		return "", err
 	}
-
-	req.Header.Set("Authorization", "Api-Token "+token)
-	res, err := d.httpClient.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		d.Log.Error(err, "Error while creating request")
 		return "", err
@@ -76,28 +130,174 @@ func (d *KeptnDynatraceProvider) EvaluateQuery(ctx context.Context, objective kl
 		d.Log.Error(err, "Error while parsing response")
 		return "", err
 	}
+	if res.StatusCode < 200 || res.StatusCode > 299 || result.Error != nil {
+		message := ""
+		if result.Error != nil {
+			message = result.Error.Message
+		}
+		return "", fmt.Errorf("query failed with status code %d: %s", res.StatusCode, message)
+	}
 
-	return fmt.Sprintf("%f", d.getSingleValue(result)), nil
+	value, err := d.getSingleValue(result, query)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%f", value), nil
 }
 
-func (d *KeptnDynatraceProvider) getSingleValue(result DynatraceResponse) float64 {
-	var sum float64 = 0
-	var count uint64 = 0
+// getDynatraceQueryParams returns the metric selector, timeframe, resolution and entity selector of the query.
+// A timeframe relative to the start of the evaluation is sent as absolute timestamps, otherwise the timeframe
+// starts the interval of the range before the query, or defaults to the one of Dynatrace if there is no range.
+func getDynatraceQueryParams(ctx context.Context, objective klcv1alpha2.Objective, query klcv1alpha2.DynatraceQuery) (url.Values, error) {
+	params := url.Values{}
+	params.Set("metricSelector", objective.Query)
+
+	if query.From != nil || query.To != nil {
+		start := PhaseStartFromContext(ctx)
+		if start.IsZero() {
+			return nil, errors.New("the timeframe relative to the start of the evaluation requires its start time")
+		}
+		if query.From != nil {
+			params.Set("from", strconv.FormatInt(start.Add(query.From.Duration).UnixMilli(), 10))
+		}
+		if query.To != nil {
+			params.Set("to", strconv.FormatInt(start.Add(query.To.Duration).UnixMilli(), 10))
+		}
+	}
+	if query.From == nil && objective.Range != nil {
+		params.Set("from", fmt.Sprintf("now-%ds", int64(objective.GetRangeInterval().Seconds())))
+	}
+
+	if query.Resolution != "" {
+		params.Set("resolution", query.Resolution)
+	}
+	if query.EntitySelector != "" {
+		params.Set("entitySelector", query.EntitySelector)
+	}
+	return params, nil
+}
+
+// getSingleValue reduces the data points of all series in the response, ordered by time,
+// with the aggregation of the query
+func (d *KeptnDynatraceProvider) getSingleValue(result DynatraceResponse, query klcv1alpha2.DynatraceQuery) (float64, error) {
+	type point struct {
+		timestamp int64
+		value     float64
+	}
+	points := []point{}
 	for _, r := range result.Result {
-		for _, points := range r.Data {
-			for _, v := range points.Values {
-				if v != nil {
-					sum += *v
-					count++
+		for _, data := range r.Data {
+			for i, v := range data.Values {
+				if v == nil {
+					continue
+				}
+				p := point{value: *v}
+				if i < len(data.Timestamps) {
+					p.timestamp = data.Timestamps[i]
 				}
+				points = append(points, p)
 			}
 		}
 	}
-	if count < 1 {
-		// cannot dive by zero
-		return 0
+	if len(points) == 0 {
+		return 0, errors.New("no data points returned by the query")
+	}
+	// the series are merged, so that last is the newest data point of all series
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].timestamp < points[j].timestamp
+	})
+	values := make([]float64, 0, len(points))
+	for _, p := range points {
+		values = append(values, p.value)
+	}
+
+	switch aggregation := query.GetAggregation(); aggregation {
+	case klcv1alpha2.DynatraceAggregationSum:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum, nil
+	case klcv1alpha2.DynatraceAggregationPercentile:
+		return percentile(values, float64(query.GetPercentile())), nil
+	default:
+		// the remaining aggregations are shared with range queries
+		return aggregate(values, klcv1alpha2.RangeAggregation(aggregation))
+	}
+}
+
+// authorize adds the API token or the OAuth access token to the request
+func (d *KeptnDynatraceProvider) authorize(ctx context.Context, req *http.Request, provider klcv1alpha2.KeptnEvaluationProvider) error {
+	if provider.Spec.OAuth != nil {
+		if provider.HasSecretDefined() {
+			return errors.New("the SecretKeyRef and OAuth properties cannot be used together")
+		}
+		token, err := d.getOAuthToken(ctx, provider)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+
+	token, err := d.getDTApiToken(ctx, provider)
+	if err != nil {
+		return err
 	}
-	return sum / float64(count)
+	req.Header.Set("Authorization", "Api-Token "+token)
+	return nil
+}
+
+// getOAuthToken requests an access token with the client credentials of the provider, or returns the cached one
+func (d *KeptnDynatraceProvider) getOAuthToken(ctx context.Context, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	// the generation only changes with the spec, so that status updates of the health checks keep the token
+	cacheKey := fmt.Sprintf("%s/%d", provider.UID, provider.Generation)
+	if provider.UID != "" {
+		if token, ok := oauthTokens.get(cacheKey); ok {
+			return token.AccessToken, nil
+		}
+	}
+
+	oauth := provider.Spec.OAuth
+	clientID, err := getSecretValue(ctx, d.k8sClient, provider.Namespace, oauth.ClientID)
+	if err != nil {
+		return "", err
+	}
+	clientSecret, err := getSecretValue(ctx, d.k8sClient, provider.Namespace, oauth.ClientSecret)
+	if err != nil {
+		return "", err
+	}
+	config := clientcredentials.Config{
+		ClientID:     string(clientID),
+		ClientSecret: string(clientSecret),
+		TokenURL:     oauth.TokenURL,
+		Scopes:       oauth.Scopes,
+		AuthStyle:    oauth2.AuthStyleInParams,
+	}
+	if config.TokenURL == "" {
+		config.TokenURL = DynatraceDefaultTokenURL
+	}
+	if oauth.Resource != "" {
+		config.EndpointParams = url.Values{"resource": []string{oauth.Resource}}
+	}
+
+	// a custom token endpoint is requested with the TLS configuration and headers of the provider,
+	// the Dynatrace SSO with the system roots
+	httpClient := &d.httpClient
+	if oauth.TokenURL != "" {
+		httpClient, err = newProviderTransportClient(ctx, d.k8sClient, d.httpClient, provider)
+		if err != nil {
+			return "", err
+		}
+	}
+	token, err := config.Token(context.WithValue(ctx, oauth2.HTTPClient, httpClient))
+	if err != nil {
+		return "", fmt.Errorf("could not request an OAuth access token: %w", err)
+	}
+	if provider.UID != "" {
+		oauthTokens.set(cacheKey, token)
+	}
+	return token.AccessToken, nil
 }
 
 func (d *KeptnDynatraceProvider) getDTApiToken(ctx context.Context, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
@@ -115,3 +315,21 @@ func (d *KeptnDynatraceProvider) getDTApiToken(ctx context.Context, provider klc
 	}
 	return string(apiToken), nil
 }
+
+// CheckHealth lists a single metric descriptor to check that the target server is reachable with the configured token
+func (d *KeptnDynatraceProvider) CheckHealth(ctx context.Context, provider klcv1alpha2.KeptnEvaluationProvider) error {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", provider.Spec.TargetServer+"/api/v2/metrics?pageSize=1", nil)
+	if err != nil {
+		return err
+	}
+	if err := d.authorize(ctx, req, provider); err != nil {
+		return err
+	}
+	httpClient, err := newProviderTransportClient(ctx, d.k8sClient, d.httpClient, provider)
+	if err != nil {
+		return err
+	}
+	return checkHealthResponse(httpClient.Do(req))
+}
@@ -2,11 +2,14 @@ package providers
 
 import (
 	"context"
+	"encoding/pem"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
 	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
@@ -20,33 +23,78 @@ import (
 const dtpayload = "{\"totalCount\":1,\"nextPageKey\":null,\"resolution\":\"1m\",\"result\":[{\"metricId\":\"dsfm:billing.hostunit.assigned:splitBy():sort(value(auto,descending)):avg\",\"dataPointCountRatio\":6.0E-6,\"dimensionCountRatio\":1.0E-5,\"data\":[{\"dimensions\":[],\"dimensionMap\":{},\"timestamps\":[1666090140000,1666090200000,1666090260000,1666090320000,1666090380000,1666090440000,1666090500000,1666090560000,1666090620000,1666090680000,1666090740000,1666090800000,1666090860000,1666090920000,1666090980000,1666091040000,1666091100000,1666091160000,1666091220000,1666091280000,1666091340000,1666091400000,1666091460000,1666091520000,1666091580000,1666091640000,1666091700000,1666091760000,1666091820000,1666091880000,1666091940000,1666092000000,1666092060000,1666092120000,1666092180000,1666092240000,1666092300000,1666092360000,1666092420000,1666092480000,1666092540000,1666092600000,1666092660000,1666092720000,1666092780000,1666092840000,1666092900000,1666092960000,1666093020000,1666093080000,1666093140000,1666093200000,1666093260000,1666093320000,1666093380000,1666093440000,1666093500000,1666093560000,1666093620000,1666093680000,1666093740000,1666093800000,1666093860000,1666093920000,1666093980000,1666094040000,1666094100000,1666094160000,1666094220000,1666094280000,1666094340000,1666094400000,1666094460000,1666094520000,1666094580000,1666094640000,1666094700000,1666094760000,1666094820000,1666094880000,1666094940000,1666095000000,1666095060000,1666095120000,1666095180000,1666095240000,1666095300000,1666095360000,1666095420000,1666095480000,1666095540000,1666095600000,1666095660000,1666095720000,1666095780000,1666095840000,1666095900000,1666095960000,1666096020000,1666096080000,1666096140000,1666096200000,1666096260000,1666096320000,1666096380000,1666096440000,1666096500000,1666096560000,1666096620000,1666096680000,1666096740000,1666096800000,1666096860000,1666096920000,1666096980000,1666097040000,1666097100000,1666097160000,1666097220000,1666097280000,1666097340000],\"values\":[null,null,null,null,null,null,50,null,null,null,null,null,null,null,null,null,null,null,null,null,null,50,null,null,null,null,null,null,null,null,null,null,null,null,null,null,50,null,null,null,null,null,null,null,null,null,null,null,null,null,null,50,null,null,null,null,null,null,null,null,null,null,null,null,null,null,50,null,null,null,null,null,null,null,null,null,null,null,null,null,null,50,null,null,null,null,null,null,null,null,null,null,null,null,null,null,50,null,null,null,null,null,null,null,null,null,null,null,null,null,null,50,null,null,null,null,null,null,null,null,null]}]}]}"
 
 func TestGetSingleValue(t *testing.T) {
-	v := 5.0
+	values := func(values ...float64) []*float64 {
+		result := []*float64{}
+		for i := range values {
+			result = append(result, &values[i])
+		}
+		return result
+	}
+	response := DynatraceResponse{
+		Result: []DynatraceResult{
+			{
+				Data: []DynatraceData{
+					{
+						Timestamps: []int64{1, 5, 6},
+						Values:     append(values(1, 2), nil),
+					},
+					{
+						Timestamps: []int64{2, 3, 4},
+						Values:     values(3, 4, 10),
+					},
+				},
+			},
+		},
+	}
+
 	tests := []struct {
 		name   string
 		input  DynatraceResponse
+		query  klcv1alpha2.DynatraceQuery
 		result float64
+		err    bool
 	}{
 		{
-			name: "happy path",
-			input: DynatraceResponse{
-				Result: []DynatraceResult{
-					{
-						Data: []DynatraceData{
-							{
-								Values: []*float64{&v, &v, &v},
-							},
-						},
-					},
-				},
-			},
-			result: v,
+			name:   "avg by default",
+			input:  response,
+			result: 4,
+		},
+		{
+			name:   "max",
+			input:  response,
+			query:  klcv1alpha2.DynatraceQuery{Aggregation: klcv1alpha2.DynatraceAggregationMax},
+			result: 10,
+		},
+		{
+			name:   "min",
+			input:  response,
+			query:  klcv1alpha2.DynatraceQuery{Aggregation: klcv1alpha2.DynatraceAggregationMin},
+			result: 1,
+		},
+		{
+			name:   "sum",
+			input:  response,
+			query:  klcv1alpha2.DynatraceQuery{Aggregation: klcv1alpha2.DynatraceAggregationSum},
+			result: 20,
+		},
+		{
+			name:   "last",
+			input:  response,
+			query:  klcv1alpha2.DynatraceQuery{Aggregation: klcv1alpha2.DynatraceAggregationLast},
+			result: 2, // the newest data point of all series
+		},
+		{
+			name:   "percentile",
+			input:  response,
+			query:  klcv1alpha2.DynatraceQuery{Aggregation: klcv1alpha2.DynatraceAggregationPercentile, Percentile: 50},
+			result: 3,
 		},
 		{
 			name: "empty path",
 			input: DynatraceResponse{
 				Result: []DynatraceResult{},
 			},
-			result: 0.0,
+			err: true,
 		},
 		{
 			name: "no data",
@@ -57,22 +105,7 @@ func TestGetSingleValue(t *testing.T) {
 					},
 				},
 			},
-			result: 0.0,
-		},
-		{
-			name: "no values",
-			input: DynatraceResponse{
-				Result: []DynatraceResult{
-					{
-						Data: []DynatraceData{
-							{
-								Values: []*float64{},
-							},
-						},
-					},
-				},
-			},
-			result: 0.0,
+			err: true,
 		},
 		{
 			name: "nil values",
@@ -87,20 +120,100 @@ func TestGetSingleValue(t *testing.T) {
 					},
 				},
 			},
-			result: 0.0,
+			err: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			kdp := KeptnDynatraceProvider{}
-			r := kdp.getSingleValue(tt.input)
+			r, err := kdp.getSingleValue(tt.input, tt.query)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
 			require.Equal(t, tt.result, r)
 		})
 
 	}
 }
 
+func TestGetDynatraceQueryParams(t *testing.T) {
+	start := time.UnixMilli(1666090140000)
+	ctx := ContextWithPhaseStart(context.TODO(), start)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		objective klcv1alpha2.Objective
+		params    url.Values
+		err       bool
+	}{
+		{
+			name:      "metric selector only",
+			ctx:       ctx,
+			objective: klcv1alpha2.Objective{Query: "builtin:service.response.time:avg"},
+			params:    url.Values{"metricSelector": {"builtin:service.response.time:avg"}},
+		},
+		{
+			name: "range",
+			ctx:  ctx,
+			objective: klcv1alpha2.Objective{
+				Query: "builtin:service.response.time:avg",
+				Range: &klcv1alpha2.QueryRange{Interval: metav1.Duration{Duration: 10 * time.Minute}},
+			},
+			params: url.Values{"metricSelector": {"builtin:service.response.time:avg"}, "from": {"now-600s"}},
+		},
+		{
+			name: "relative to the phase start",
+			ctx:  ctx,
+			objective: klcv1alpha2.Objective{
+				Query: "builtin:service.response.time:avg",
+				Dynatrace: &klcv1alpha2.DynatraceQuery{
+					From:           &metav1.Duration{Duration: -10 * time.Minute},
+					To:             &metav1.Duration{Duration: 5 * time.Minute},
+					Resolution:     "1m",
+					EntitySelector: `type("SERVICE"),tag("app:podtato-head")`,
+				},
+				Range: &klcv1alpha2.QueryRange{Interval: metav1.Duration{Duration: 10 * time.Minute}},
+			},
+			params: url.Values{
+				"metricSelector": {"builtin:service.response.time:avg"},
+				"from":           {"1666089540000"},
+				"to":             {"1666090440000"},
+				"resolution":     {"1m"},
+				"entitySelector": {`type("SERVICE"),tag("app:podtato-head")`},
+			},
+		},
+		{
+			name: "unknown phase start",
+			ctx:  context.TODO(),
+			objective: klcv1alpha2.Objective{
+				Query:     "builtin:service.response.time:avg",
+				Dynatrace: &klcv1alpha2.DynatraceQuery{From: &metav1.Duration{Duration: -10 * time.Minute}},
+			},
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := klcv1alpha2.DynatraceQuery{}
+			if tt.objective.Dynatrace != nil {
+				query = *tt.objective.Dynatrace
+			}
+			params, err := getDynatraceQueryParams(tt.ctx, tt.objective, query)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.params, params)
+		})
+	}
+}
+
 func TestEvaluateQuery_CorrectHTTP(t *testing.T) {
 	const query = "myspecialquery"
 	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -333,3 +446,249 @@ func TestEvaluateQuery_HappyPath(t *testing.T) {
 	require.Nil(t, e)
 	require.Equal(t, fmt.Sprintf("%f", 50.0), r)
 }
+
+func TestEvaluateQuery_ErrorResponse(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, err := w.Write([]byte(`{"error":{"code":400,"message":"The metric selector is invalid"}}`))
+		require.Nil(t, err)
+	}))
+	defer svr.Close()
+	apiToken := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "myapitoken",
+		},
+		Data: map[string][]byte{
+			"mykey": []byte("mytoken"),
+		},
+	}
+	fakeClient, err := fake.NewClient(apiToken)
+	require.Nil(t, err)
+	kdp := KeptnDynatraceProvider{
+		httpClient: http.Client{},
+		Log:        ctrl.Log.WithName("testytest"),
+		k8sClient:  fakeClient,
+	}
+	p := klcv1alpha2.KeptnEvaluationProvider{
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+			SecretKeyRef: v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{
+					Name: "myapitoken",
+				},
+				Key: "mykey",
+			},
+			TargetServer: svr.URL,
+		},
+	}
+	_, e := kdp.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: "invalid"}, p)
+	require.NotNil(t, e)
+	require.Contains(t, e.Error(), "The metric selector is invalid")
+}
+
+func TestEvaluateQuery_OAuth(t *testing.T) {
+	tokenRequests := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		require.Nil(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+		require.Equal(t, "myclient", r.Form.Get("client_id"))
+		require.Equal(t, "mysecret", r.Form.Get("client_secret"))
+		require.Equal(t, "storage:metrics:read", r.Form.Get("scope"))
+		require.Equal(t, "urn:dtaccount:abc", r.Form.Get("resource"))
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"access_token":"myaccesstoken","token_type":"Bearer","expires_in":300}`))
+		require.Nil(t, err)
+	}))
+	defer tokenServer.Close()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer myaccesstoken", r.Header.Get("Authorization"))
+		_, err := w.Write([]byte(dtpayload))
+		require.Nil(t, err)
+	}))
+	defer svr.Close()
+
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "oauth",
+		},
+		Data: map[string][]byte{
+			"id":     []byte("myclient"),
+			"secret": []byte("mysecret"),
+		},
+	}
+	fakeClient, err := fake.NewClient(credentials)
+	require.Nil(t, err)
+	p := klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:        "oauth-provider",
+			Generation: 1,
+		},
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+			TargetServer: svr.URL,
+			OAuth: &klcv1alpha2.OAuthClientCredentials{
+				TokenURL: tokenServer.URL,
+				ClientID: v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "oauth"},
+					Key:                  "id",
+				},
+				ClientSecret: v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "oauth"},
+					Key:                  "secret",
+				},
+				Scopes:   []string{"storage:metrics:read"},
+				Resource: "urn:dtaccount:abc",
+			},
+		},
+	}
+
+	evaluate := func() {
+		// a new provider implementation is created for every evaluation
+		kdp := KeptnDynatraceProvider{
+			httpClient: http.Client{},
+			Log:        ctrl.Log.WithName("testytest"),
+			k8sClient:  fakeClient,
+		}
+		r, e := kdp.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: "myquery"}, p)
+		require.Nil(t, e)
+		require.Equal(t, "50.000000", r)
+	}
+	evaluate()
+	evaluate()
+	// the access token is reused until it expires
+	require.Equal(t, 1, tokenRequests)
+
+	// status updates do not change the generation
+	p.ResourceVersion = "2"
+	evaluate()
+	require.Equal(t, 1, tokenRequests)
+
+	// a changed provider requests a new access token
+	p.Generation = 2
+	evaluate()
+	require.Equal(t, 2, tokenRequests)
+
+	kdp := KeptnDynatraceProvider{
+		httpClient: http.Client{},
+		Log:        ctrl.Log.WithName("testytest"),
+		k8sClient:  fakeClient,
+	}
+
+	// the API token cannot be used together with OAuth
+	p.Spec.SecretKeyRef = v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: "oauth"},
+		Key:                  "secret",
+	}
+	_, e := kdp.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: "myquery"}, p)
+	require.NotNil(t, e)
+}
+
+func TestEvaluateQuery_OAuthTLS(t *testing.T) {
+	tokenServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"access_token":"myaccesstoken","token_type":"Bearer","expires_in":300}`))
+		require.Nil(t, err)
+	}))
+	defer tokenServer.Close()
+	svr := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer myaccesstoken", r.Header.Get("Authorization"))
+		_, err := w.Write([]byte(dtpayload))
+		require.Nil(t, err)
+	}))
+	defer svr.Close()
+
+	// both servers use the same certificate of the httptest package
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "oauth",
+		},
+		Data: map[string][]byte{
+			"id":     []byte("myclient"),
+			"secret": []byte("mysecret"),
+			"ca.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tokenServer.Certificate().Raw}),
+		},
+	}
+	fakeClient, err := fake.NewClient(credentials)
+	require.Nil(t, err)
+	kdp := KeptnDynatraceProvider{
+		httpClient: http.Client{},
+		Log:        ctrl.Log.WithName("testytest"),
+		k8sClient:  fakeClient,
+	}
+	p := klcv1alpha2.KeptnEvaluationProvider{
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+			TargetServer: svr.URL,
+			OAuth: &klcv1alpha2.OAuthClientCredentials{
+				TokenURL:     tokenServer.URL,
+				ClientID:     v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "oauth"}, Key: "id"},
+				ClientSecret: v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "oauth"}, Key: "secret"},
+			},
+			TLS: &klcv1alpha2.TLSConfig{
+				CA: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "oauth"}, Key: "ca.crt"},
+			},
+		},
+	}
+
+	r, e := kdp.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: "myquery"}, p)
+	require.Nil(t, e)
+	require.Equal(t, "50.000000", r)
+}
+
+func TestEvaluateQuery_OAuthClusterProvider(t *testing.T) {
+	tokenRequests := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"access_token":"myaccesstoken","token_type":"Bearer","expires_in":300}`))
+		require.Nil(t, err)
+	}))
+	defer tokenServer.Close()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer myaccesstoken", r.Header.Get("Authorization"))
+		_, err := w.Write([]byte(dtpayload))
+		require.Nil(t, err)
+	}))
+	defer svr.Close()
+
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "oauth",
+			Namespace: "keptn-lifecycle-toolkit-system",
+		},
+		Data: map[string][]byte{
+			"id":     []byte("myclient"),
+			"secret": []byte("mysecret"),
+		},
+	}
+	fakeClient, err := fake.NewClient(credentials)
+	require.Nil(t, err)
+	clusterProvider := &klcv1alpha2.ClusterKeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "dynatrace",
+			UID:        "cluster-oauth-provider",
+			Generation: 1,
+		},
+		Spec: klcv1alpha2.ClusterKeptnEvaluationProviderSpec{
+			KeptnEvaluationProviderSpec: klcv1alpha2.KeptnEvaluationProviderSpec{
+				TargetServer: svr.URL,
+				OAuth: &klcv1alpha2.OAuthClientCredentials{
+					TokenURL:     tokenServer.URL,
+					ClientID:     v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "oauth"}, Key: "id"},
+					ClientSecret: v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "oauth"}, Key: "secret"},
+				},
+			},
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		// the cluster provider is converted and a new provider implementation is created for every evaluation
+		kdp := KeptnDynatraceProvider{
+			httpClient: http.Client{},
+			Log:        ctrl.Log.WithName("testytest"),
+			k8sClient:  fakeClient,
+		}
+		r, e := kdp.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: "myquery"}, clusterProvider.ToEvaluationProvider("keptn-lifecycle-toolkit-system"))
+		require.Nil(t, e)
+		require.Equal(t, "50.000000", r)
+	}
+	require.Equal(t, 1, tokenRequests)
+}
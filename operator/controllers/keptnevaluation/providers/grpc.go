@@ -0,0 +1,196 @@
+package providers
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EvaluateQueryMethod is the full name of the gRPC method external evaluation providers have to implement,
+// see evaluationprovider.proto for the contract
+const EvaluateQueryMethod = "/keptn.evaluation.v1.EvaluationProvider/EvaluateQuery"
+
+// EvaluationProviderServer is the server API of external evaluation providers
+type EvaluationProviderServer interface {
+	EvaluateQuery(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+}
+
+// RegisterEvaluationProviderServer registers the implementation of an external evaluation provider at the gRPC server
+func RegisterEvaluationProviderServer(s *grpc.Server, srv EvaluationProviderServer) {
+	s.RegisterService(&evaluationProviderServiceDesc, srv)
+}
+
+var evaluationProviderServiceDesc = grpc.ServiceDesc{
+	ServiceName: "keptn.evaluation.v1.EvaluationProvider",
+	HandlerType: (*EvaluationProviderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "EvaluateQuery",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				request := &structpb.Struct{}
+				if err := dec(request); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(EvaluationProviderServer).EvaluateQuery(ctx, request)
+				}
+				info := &grpc.UnaryServerInfo{
+					Server:     srv,
+					FullMethod: EvaluateQueryMethod,
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(EvaluationProviderServer).EvaluateQuery(ctx, req.(*structpb.Struct))
+				}
+				return interceptor(ctx, request, info, handler)
+			},
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "evaluationprovider.proto",
+}
+
+// KeptnGRPCProvider delegates the evaluation of objectives to an external provider over gRPC
+type KeptnGRPCProvider struct {
+	Log       logr.Logger
+	k8sClient client.Client
+}
+
+func (g *KeptnGRPCProvider) EvaluateQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	request, err := newEvaluateQueryRequest(ctx, objective, provider)
+	if err != nil {
+		return "", err
+	}
+
+	transportCredentials, err := g.getTransportCredentials(ctx, provider)
+	if err != nil {
+		return "", err
+	}
+	headers, err := getProviderHeaders(ctx, g.k8sClient, provider)
+	if err != nil {
+		return "", err
+	}
+	for name, values := range headers {
+		ctx = metadata.AppendToOutgoingContext(ctx, strings.ToLower(name), strings.Join(values, ","))
+	}
+
+	g.Log.Info("Running query: " + objective.Query + " on " + provider.Spec.TargetServer)
+	conn, err := grpc.DialContext(ctx, provider.Spec.TargetServer, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		g.Log.Error(err, "Error while connecting to the provider")
+		return "", err
+	}
+	defer func() {
+		err := conn.Close()
+		if err != nil {
+			g.Log.Error(err, "Could not close connection")
+		}
+	}()
+
+	response := &structpb.Struct{}
+	if err := conn.Invoke(ctx, EvaluateQueryMethod, request, response); err != nil {
+		g.Log.Error(err, "Error while evaluating the query")
+		return "", err
+	}
+
+	value, ok := response.GetFields()["value"]
+	if !ok {
+		return "", fmt.Errorf("no value in the response of the provider")
+	}
+	switch v := value.GetKind().(type) {
+	case *structpb.Value_NumberValue:
+		return strconv.FormatFloat(v.NumberValue, 'f', -1, 64), nil
+	case *structpb.Value_StringValue:
+		return toNumericString(v.StringValue)
+	default:
+		return "", fmt.Errorf("value %v of the response of the provider is not a number", value.AsInterface())
+	}
+}
+
+// newEvaluateQueryRequest returns the request holding the objective with the rendered query,
+// the variables of the lifecycle context and the provider
+func newEvaluateQueryRequest(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (*structpb.Struct, error) {
+	objectiveFields, err := toStructFields(objective)
+	if err != nil {
+		return nil, err
+	}
+	variables := map[string]interface{}{}
+	for name, value := range VariablesFromContext(ctx) {
+		variables[name] = value
+	}
+	return structpb.NewStruct(map[string]interface{}{
+		"objective": objectiveFields,
+		"context":   variables,
+		"provider": map[string]interface{}{
+			"name":         provider.Name,
+			"namespace":    provider.Namespace,
+			"targetServer": provider.Spec.TargetServer,
+		},
+	})
+}
+
+func toStructFields(obj interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// CheckHealth checks that a connection to the target server can be established
+func (g *KeptnGRPCProvider) CheckHealth(ctx context.Context, provider klcv1alpha2.KeptnEvaluationProvider) error {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	transportCredentials, err := g.getTransportCredentials(ctx, provider)
+	if err != nil {
+		return err
+	}
+	conn, err := grpc.DialContext(ctx, provider.Spec.TargetServer, grpc.WithTransportCredentials(transportCredentials), grpc.WithBlock())
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// getTransportCredentials returns the credentials of the connection to the target server, which uses TLS
+// verified with the system roots unless the provider configures its own TLS settings or explicitly opts out of TLS.
+// Credentials of the provider are never sent over a connection without TLS.
+func (g *KeptnGRPCProvider) getTransportCredentials(ctx context.Context, provider klcv1alpha2.KeptnEvaluationProvider) (credentials.TransportCredentials, error) {
+	if provider.Spec.Insecure {
+		if provider.Spec.TLS != nil {
+			return nil, errors.New("the TLS and insecure properties cannot be used together")
+		}
+		if provider.HasSecretDefined() || provider.Spec.BasicAuth != nil {
+			return nil, errors.New("credentials are not sent to the target server over an insecure connection")
+		}
+		return insecure.NewCredentials(), nil
+	}
+	if provider.Spec.TLS == nil {
+		return credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12}), nil
+	}
+	tlsConfig, err := newTLSConfig(ctx, g.k8sClient, provider.Namespace, provider.Spec.TLS)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
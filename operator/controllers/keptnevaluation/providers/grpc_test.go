@@ -0,0 +1,223 @@
+package providers
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+type stubEvaluationProviderServer struct {
+	request  *structpb.Struct
+	metadata metadata.MD
+	response *structpb.Struct
+	err      error
+}
+
+func (s *stubEvaluationProviderServer) EvaluateQuery(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error) {
+	s.request = request
+	s.metadata, _ = metadata.FromIncomingContext(ctx)
+	_, hasDeadline := ctx.Deadline()
+	if !hasDeadline {
+		return nil, status.Error(codes.InvalidArgument, "no deadline")
+	}
+	return s.response, s.err
+}
+
+func startStubServer(t *testing.T, stub *stubEvaluationProviderServer, opts ...grpc.ServerOption) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	server := grpc.NewServer(opts...)
+	RegisterEvaluationProviderServer(server, stub)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+// startTLSStubServer starts a stub server with the certificate of the httptest package for 127.0.0.1
+// and returns its address and the PEM encoded certificate
+func startTLSStubServer(t *testing.T, stub *stubEvaluationProviderServer) (string, []byte) {
+	svr := httptest.NewUnstartedServer(nil)
+	svr.StartTLS()
+	certificate := svr.TLS.Certificates[0]
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: svr.Certificate().Raw})
+	svr.Close()
+	serverCredentials := credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12})
+	return startStubServer(t, stub, grpc.Creds(serverCredentials)), ca
+}
+
+func TestGRPCProvider_HappyPath(t *testing.T) {
+	response, err := structpb.NewStruct(map[string]interface{}{"value": 42.5})
+	require.Nil(t, err)
+	stub := &stubEvaluationProviderServer{response: response}
+	address, ca := startTLSStubServer(t, stub)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "grpc-token",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"token":  []byte("mytoken"),
+			"ca.crt": ca,
+		},
+	}
+	fakeClient, err := fake.NewClient(secret)
+	require.Nil(t, err)
+
+	kgp := KeptnGRPCProvider{
+		Log:       ctrl.Log.WithName("testytest"),
+		k8sClient: fakeClient,
+	}
+	obj := klcv1alpha2.Objective{
+		Name:             "orders",
+		Query:            "open_orders{app=\"shop\"}",
+		EvaluationTarget: "<100",
+	}
+	p := klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "in-house",
+			Namespace: "default",
+		},
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+			TargetServer: address,
+			SecretKeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "grpc-token"},
+				Key:                  "token",
+			},
+			TLS: &klcv1alpha2.TLSConfig{
+				CA: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "grpc-token"}, Key: "ca.crt"},
+			},
+			Headers: map[string]string{"X-Tenant": "shop"},
+		},
+	}
+	ctx := ContextWithVariables(context.TODO(), map[string]string{"workload": "shop-frontend"})
+	r, e := kgp.EvaluateQuery(ctx, obj, p)
+	require.Nil(t, e)
+	require.Equal(t, "42.5", r)
+
+	request := stub.request.AsMap()
+	require.Equal(t, map[string]interface{}{"name": "orders", "query": "open_orders{app=\"shop\"}", "evaluationTarget": "<100"}, request["objective"])
+	require.Equal(t, map[string]interface{}{"workload": "shop-frontend"}, request["context"])
+	require.Equal(t, map[string]interface{}{"name": "in-house", "namespace": "default", "targetServer": address}, request["provider"])
+	require.Equal(t, []string{"Bearer mytoken"}, stub.metadata.Get("authorization"))
+	require.Equal(t, []string{"shop"}, stub.metadata.Get("x-tenant"))
+}
+
+func TestGRPCProvider_Responses(t *testing.T) {
+	tests := []struct {
+		name     string
+		response map[string]interface{}
+		err      error
+		result   string
+		wantErr  bool
+	}{
+		{
+			name:     "string value",
+			response: map[string]interface{}{"value": "12"},
+			result:   "12",
+		},
+		{
+			name:     "non numeric value",
+			response: map[string]interface{}{"value": "twelve"},
+			wantErr:  true,
+		},
+		{
+			name:     "boolean value",
+			response: map[string]interface{}{"value": true},
+			wantErr:  true,
+		},
+		{
+			name:     "missing value",
+			response: map[string]interface{}{},
+			wantErr:  true,
+		},
+		{
+			name:    "error status",
+			err:     status.Error(codes.Unavailable, "backend down"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := structpb.NewStruct(tt.response)
+			require.Nil(t, err)
+			address := startStubServer(t, &stubEvaluationProviderServer{response: response, err: tt.err})
+
+			kgp := KeptnGRPCProvider{
+				Log: ctrl.Log.WithName("testytest"),
+			}
+			p := klcv1alpha2.KeptnEvaluationProvider{
+				Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+					TargetServer: address,
+					Insecure:     true,
+				},
+			}
+			r, e := kgp.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: "query"}, p)
+			if tt.wantErr {
+				require.NotNil(t, e)
+				return
+			}
+			require.Nil(t, e)
+			require.Equal(t, tt.result, r)
+		})
+	}
+}
+
+func TestGRPCProvider_Insecure(t *testing.T) {
+	response, err := structpb.NewStruct(map[string]interface{}{"value": 1})
+	require.Nil(t, err)
+	stub := &stubEvaluationProviderServer{response: response}
+	address := startStubServer(t, stub)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "grpc-token", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("mytoken")},
+	}
+	fakeClient, err := fake.NewClient(secret)
+	require.Nil(t, err)
+	kgp := KeptnGRPCProvider{
+		Log:       ctrl.Log.WithName("testytest"),
+		k8sClient: fakeClient,
+	}
+	p := klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "in-house", Namespace: "default"},
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+			TargetServer: address,
+			SecretKeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "grpc-token"},
+				Key:                  "token",
+			},
+		},
+	}
+
+	// TLS is used by default, the plaintext server cannot be reached
+	_, err = kgp.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: "query"}, p)
+	require.NotNil(t, err)
+	require.Nil(t, stub.request)
+
+	// the token is not sent over an insecure connection
+	p.Spec.Insecure = true
+	_, err = kgp.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: "query"}, p)
+	require.ErrorContains(t, err, "insecure connection")
+	require.Nil(t, stub.request)
+	require.NotNil(t, kgp.CheckHealth(context.TODO(), p))
+}
@@ -0,0 +1,130 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KeptnHTTPProvider evaluates objectives by calling an arbitrary HTTP endpoint
+// and extracting a numeric value from the JSON response.
+// The authentication, headers and TLS configuration of the provider are applied to every request.
+type KeptnHTTPProvider struct {
+	Log        logr.Logger
+	httpClient http.Client
+	k8sClient  client.Client
+}
+
+func (h *KeptnHTTPProvider) EvaluateQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	if objective.HTTP == nil {
+		return "", errors.New("the http property of the objective is missing")
+	}
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	method := objective.HTTP.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	qURL := provider.Spec.TargetServer + objective.Query
+	if err := checkTargetHost(provider.Spec.TargetServer, qURL); err != nil {
+		return "", err
+	}
+	h.Log.Info("Running query: " + method + " " + qURL)
+
+	var body io.Reader
+	if objective.HTTP.Body != "" {
+		body = strings.NewReader(objective.HTTP.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, qURL, body)
+	if err != nil {
+		h.Log.Error(err, "Error while creating request")
+		return "", err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	for name, value := range objective.HTTP.Headers {
+		req.Header.Set(name, value)
+	}
+
+	httpClient, err := newProviderHTTPClient(ctx, h.k8sClient, h.httpClient, provider)
+	if err != nil {
+		return "", err
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		h.Log.Error(err, "Error while sending request")
+		return "", err
+	}
+	defer func() {
+		err := res.Body.Close()
+		if err != nil {
+			h.Log.Error(err, "Could not close request body")
+		}
+	}()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return "", fmt.Errorf("request failed with status code %d", res.StatusCode)
+	}
+
+	// we ignore the error here because we fail later while unmarshalling
+	b, _ := io.ReadAll(res.Body)
+	var result interface{}
+	if err := json.Unmarshal(b, &result); err != nil {
+		h.Log.Error(err, "Error while parsing response")
+		return "", err
+	}
+
+	return extractJSONPathValue(result, objective.HTTP.JSONPath)
+}
+
+// checkTargetHost makes sure that the query does not change the host of the target server,
+// so that the credentials of the provider are never sent to another server
+func checkTargetHost(targetServer string, queryURL string) error {
+	target, err := url.Parse(targetServer)
+	if err != nil {
+		return err
+	}
+	query, err := url.Parse(queryURL)
+	if err != nil {
+		return err
+	}
+	if query.Scheme != target.Scheme || query.Host != target.Host || query.User != nil {
+		return fmt.Errorf("the query must not change the host of the target server %s", targetServer)
+	}
+	return nil
+}
+
+// CheckHealth sends a GET request to the target server. As the server may not serve its root path,
+// every response except server errors is regarded as healthy.
+func (h *KeptnHTTPProvider) CheckHealth(ctx context.Context, provider klcv1alpha2.KeptnEvaluationProvider) error {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.Spec.TargetServer, nil)
+	if err != nil {
+		return err
+	}
+	httpClient, err := newProviderHTTPClient(ctx, h.k8sClient, h.httpClient, provider)
+	if err != nil {
+		return err
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 500 {
+		return fmt.Errorf("health check failed with status code %d", res.StatusCode)
+	}
+	return nil
+}
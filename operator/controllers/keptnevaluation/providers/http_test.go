@@ -0,0 +1,259 @@
+package providers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const httpPayload = `{"status":"ok","data":{"checks":[{"name":"db","latency":12.5},{"name":"cache","latency":"3"}],"orders":42}}`
+
+func TestHTTPProvider_HappyPath(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "POST", r.Method)
+		require.Equal(t, "/metrics/orders", r.URL.Path)
+		require.Equal(t, "Bearer secretValue", r.Header.Get("Authorization"))
+		require.Equal(t, "tenant-a", r.Header.Get("X-Tenant"))
+		b, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+		require.Equal(t, `{"window":"5m"}`, string(b))
+		_, err = w.Write([]byte(httpPayload))
+		require.Nil(t, err)
+	}))
+	defer svr.Close()
+
+	secretName, secretKey, secretValue := "secretName", "secretKey", "secretValue"
+	apiToken := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: "",
+		},
+		Data: map[string][]byte{
+			secretKey: []byte(secretValue),
+		},
+	}
+	fakeClient, err := fake.NewClient(apiToken)
+	require.Nil(t, err)
+
+	khp := KeptnHTTPProvider{
+		httpClient: http.Client{},
+		Log:        ctrl.Log.WithName("testytest"),
+		k8sClient:  fakeClient,
+	}
+	obj := klcv1alpha2.Objective{
+		Query: "/metrics/orders",
+		HTTP: &klcv1alpha2.HTTPRequest{
+			Method:   "POST",
+			Headers:  map[string]string{"X-Tenant": "tenant-a"},
+			Body:     `{"window":"5m"}`,
+			JSONPath: ".data.orders",
+		},
+	}
+	p := klcv1alpha2.KeptnEvaluationProvider{
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+			SecretKeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: secretName,
+				},
+				Key: secretKey,
+			},
+			TargetServer: svr.URL,
+		},
+	}
+	r, e := khp.EvaluateQuery(context.TODO(), obj, p)
+	require.Nil(t, e)
+	require.Equal(t, "42", r)
+}
+
+func TestHTTPProvider_ProviderAuthentication(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "keptn", username)
+		require.Equal(t, "s3cr3t", password)
+		require.Equal(t, "tenant-a", r.Header.Get("X-Tenant"))
+		_, err := w.Write([]byte(httpPayload))
+		require.Nil(t, err)
+	}))
+	defer svr.Close()
+
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "default"},
+		Data:       map[string][]byte{"username": []byte("keptn"), "password": []byte("s3cr3t")},
+	}
+	fakeClient, err := fake.NewClient(credentials)
+	require.Nil(t, err)
+
+	khp := KeptnHTTPProvider{
+		httpClient: http.Client{},
+		Log:        ctrl.Log.WithName("testytest"),
+		k8sClient:  fakeClient,
+	}
+	obj := klcv1alpha2.Objective{
+		Query: "/metrics/orders",
+		HTTP:  &klcv1alpha2.HTTPRequest{JSONPath: ".data.orders"},
+	}
+	p := klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+			TargetServer: svr.URL,
+			BasicAuth: &klcv1alpha2.BasicAuth{
+				Username: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"}, Key: "username"},
+				Password: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"}, Key: "password"},
+			},
+			Headers: map[string]string{"X-Tenant": "tenant-a"},
+		},
+	}
+	r, e := khp.EvaluateQuery(context.TODO(), obj, p)
+	require.Nil(t, e)
+	require.Equal(t, "42", r)
+
+	require.Nil(t, khp.CheckHealth(context.TODO(), p))
+}
+
+func TestHTTPProvider_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		payload    string
+		objective  klcv1alpha2.Objective
+		message    string
+	}{
+		{
+			name:       "missing http property",
+			statusCode: http.StatusOK,
+			payload:    httpPayload,
+			objective:  klcv1alpha2.Objective{Query: "/"},
+			message:    "the http property of the objective is missing",
+		},
+		{
+			name:       "error status code",
+			statusCode: http.StatusUnauthorized,
+			payload:    httpPayload,
+			objective:  klcv1alpha2.Objective{Query: "/", HTTP: &klcv1alpha2.HTTPRequest{JSONPath: "{.data.orders}"}},
+			message:    "request failed with status code 401",
+		},
+		{
+			name:       "garbage payload",
+			statusCode: http.StatusOK,
+			payload:    "garbage",
+			objective:  klcv1alpha2.Objective{Query: "/", HTTP: &klcv1alpha2.HTTPRequest{JSONPath: "{.data.orders}"}},
+			message:    "invalid character",
+		},
+		{
+			name:       "too many values",
+			statusCode: http.StatusOK,
+			payload:    httpPayload,
+			objective:  klcv1alpha2.Objective{Query: "/", HTTP: &klcv1alpha2.HTTPRequest{JSONPath: "{.data.checks[*].latency}"}},
+			message:    "too many values in the query result",
+		},
+		{
+			name:       "query changing the host",
+			statusCode: http.StatusOK,
+			payload:    httpPayload,
+			objective:  klcv1alpha2.Objective{Query: "@attacker.example.com/", HTTP: &klcv1alpha2.HTTPRequest{JSONPath: "{.data.orders}"}},
+			message:    "the query must not change the host of the target server",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, err := w.Write([]byte(tt.payload))
+				require.Nil(t, err)
+			}))
+			defer svr.Close()
+
+			khp := KeptnHTTPProvider{
+				httpClient: http.Client{},
+				Log:        ctrl.Log.WithName("testytest"),
+			}
+			p := klcv1alpha2.KeptnEvaluationProvider{
+				Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+					TargetServer: svr.URL,
+				},
+			}
+			r, e := khp.EvaluateQuery(context.TODO(), tt.objective, p)
+			require.Equal(t, "", r)
+			require.NotNil(t, e)
+			require.Contains(t, e.Error(), tt.message)
+		})
+	}
+}
+
+func TestExtractJSONPathValue(t *testing.T) {
+	data := map[string]interface{}{
+		"data": map[string]interface{}{
+			"checks": []interface{}{
+				map[string]interface{}{"name": "db", "latency": 12.5},
+				map[string]interface{}{"name": "cache", "latency": "3"},
+			},
+			"status": "ok",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		result     string
+		err        bool
+	}{
+		{
+			name:       "number",
+			expression: "{.data.checks[0].latency}",
+			result:     "12.5",
+		},
+		{
+			name:       "numeric string without braces",
+			expression: ".data.checks[1].latency",
+			result:     "3",
+		},
+		{
+			name:       "filter",
+			expression: `{.data.checks[?(@.name=="db")].latency}`,
+			result:     "12.5",
+		},
+		{
+			name:       "not a number",
+			expression: "{.data.status}",
+			err:        true,
+		},
+		{
+			name:       "missing key",
+			expression: "{.data.missing}",
+			err:        true,
+		},
+		{
+			name:       "empty expression",
+			expression: "",
+			err:        true,
+		},
+		{
+			name:       "invalid expression",
+			expression: "{.data[}",
+			err:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := extractJSONPathValue(data, tt.expression)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.result, r)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package providers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// extractJSONPathValue returns the single numeric value the JSONPath expression selects in data.
+// Expressions can be given with or without the enclosing braces, e.g. {.data.value} or .data.value
+func extractJSONPathValue(data interface{}, expression string) (string, error) {
+	jp, err := parseJSONPath(expression)
+	if err != nil {
+		return "", err
+	}
+	results, err := jp.FindResults(data)
+	if err != nil {
+		return "", err
+	}
+
+	var values []interface{}
+	for _, result := range results {
+		for _, r := range result {
+			values = append(values, r.Interface())
+		}
+	}
+	if len(values) == 0 {
+		return "", fmt.Errorf("no values in query result")
+	} else if len(values) > 1 {
+		return "", fmt.Errorf("too many values in the query result")
+	}
+
+	value, err := toNumericString(values[0])
+	if err != nil {
+		return "", fmt.Errorf("the value selected by %s: %w", expression, err)
+	}
+	return value, nil
+}
+
+// matchesJSONPath checks if the JSONPath expression selects at least one value in data
+func matchesJSONPath(data interface{}, expression string) (bool, error) {
+	jp, err := parseJSONPath(expression)
+	if err != nil {
+		return false, err
+	}
+	jp.AllowMissingKeys(true)
+	results, err := jp.FindResults(data)
+	if err != nil {
+		return false, err
+	}
+	for _, result := range results {
+		if len(result) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func parseJSONPath(expression string) (*jsonpath.JSONPath, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, fmt.Errorf("no JSONPath expression defined")
+	}
+	if !strings.HasPrefix(expression, "{") {
+		expression = "{" + expression + "}"
+	}
+
+	jp := jsonpath.New("value")
+	if err := jp.Parse(expression); err != nil {
+		return nil, fmt.Errorf("invalid JSONPath expression %s: %w", expression, err)
+	}
+	return jp, nil
+}
+
+// toNumericString returns the number held by the value. The errors never contain the value itself,
+// as it may have been read from an object the user of the provider is not allowed to see.
+func toNumericString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil || math.IsNaN(f) {
+			return "", fmt.Errorf("value of type %T is not a number", v)
+		}
+		return strings.TrimSpace(v), nil
+	default:
+		return "", fmt.Errorf("value of type %T is not a number", value)
+	}
+}
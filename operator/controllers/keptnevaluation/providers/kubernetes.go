@@ -0,0 +1,253 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var metricsGroupVersion = schema.GroupVersion{Group: "metrics.k8s.io", Version: "v1beta1"}
+
+// sensitiveKinds are never read, as the operator could read them with its own permissions
+// and their content would end up in the status of the evaluation
+var sensitiveKinds = []schema.GroupKind{
+	{Group: corev1.GroupName, Kind: "Secret"},
+}
+
+// KeptnKubernetesProvider evaluates objectives by reading the state of the cluster from the API server.
+// Namespaced objects are only read from the namespace of the evaluation.
+type KeptnKubernetesProvider struct {
+	Log       logr.Logger
+	k8sClient client.Client
+}
+
+func (k *KeptnKubernetesProvider) EvaluateQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	query := objective.Kubernetes
+	if query == nil {
+		return "", errors.New("the kubernetes property of the objective is missing")
+	}
+	if err := CheckKubernetesKind(query); err != nil {
+		return "", err
+	}
+	k.Log.Info(fmt.Sprintf("Running %s query for %s %s", objective.Query, query.APIVersion, query.Kind))
+
+	switch klcv1alpha2.KubernetesQueryType(strings.ToLower(strings.TrimSpace(objective.Query))) {
+	case klcv1alpha2.KubernetesQueryCount:
+		return k.countResources(ctx, query, provider)
+	case klcv1alpha2.KubernetesQueryField:
+		return k.readField(ctx, query, provider)
+	case klcv1alpha2.KubernetesQueryMetrics:
+		return k.readMetrics(ctx, query, provider)
+	default:
+		return "", fmt.Errorf("query type %q not supported, use count, field or metrics", objective.Query)
+	}
+}
+
+// countResources returns the number of resources matching the selectors and the JSONPath filter
+func (k *KeptnKubernetesProvider) countResources(ctx context.Context, query *klcv1alpha2.KubernetesQuery, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	gv, err := getGroupVersion(query)
+	if err != nil {
+		return "", err
+	}
+	list, err := k.listResources(ctx, gv.WithKind(query.Kind+"List"), query, provider)
+	if err != nil {
+		return "", err
+	}
+	if query.JSONPath == "" {
+		return strconv.Itoa(len(list.Items)), nil
+	}
+
+	count := 0
+	for _, item := range list.Items {
+		matches, err := matchesJSONPath(item.Object, query.JSONPath)
+		if err != nil {
+			return "", err
+		}
+		if matches {
+			count++
+		}
+	}
+	return strconv.Itoa(count), nil
+}
+
+// readField returns the numeric value the JSONPath expression selects in a single resource
+func (k *KeptnKubernetesProvider) readField(ctx context.Context, query *klcv1alpha2.KubernetesQuery, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	if query.Name == "" {
+		return "", errors.New("the name of the resource is required for field queries")
+	}
+	gv, err := getGroupVersion(query)
+	if err != nil {
+		return "", err
+	}
+	namespace, err := getNamespace(ctx, query, provider)
+	if err != nil {
+		return "", err
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gv.WithKind(query.Kind))
+	if err := k.k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: query.Name}, obj); err != nil {
+		return "", err
+	}
+	return extractJSONPathValue(obj.Object, query.JSONPath)
+}
+
+// readMetrics returns the sum of the usage of the pods or nodes reported by the metrics.k8s.io API,
+// in cores for cpu and in bytes for memory, or the utilization of the allocatable resources of nodes in percent
+func (k *KeptnKubernetesProvider) readMetrics(ctx context.Context, query *klcv1alpha2.KubernetesQuery, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	resourceName := corev1.ResourceName(query.Resource)
+	if resourceName == "" {
+		resourceName = corev1.ResourceCPU
+	}
+	if resourceName != corev1.ResourceCPU && resourceName != corev1.ResourceMemory {
+		return "", fmt.Errorf("resource %q not supported, use cpu or memory", query.Resource)
+	}
+
+	var usage float64
+	switch query.Kind {
+	case "Pod":
+		if query.Utilization {
+			return "", errors.New("utilization is only supported for nodes")
+		}
+		list, err := k.listResources(ctx, metricsGroupVersion.WithKind("PodMetricsList"), query, provider)
+		if err != nil {
+			return "", err
+		}
+		for _, item := range list.Items {
+			containers, _, err := unstructured.NestedSlice(item.Object, "containers")
+			if err != nil {
+				return "", err
+			}
+			for _, container := range containers {
+				c, ok := container.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				value, err := getUsage(c, resourceName)
+				if err != nil {
+					return "", err
+				}
+				usage += value
+			}
+		}
+	case "Node":
+		list, err := k.listResources(ctx, metricsGroupVersion.WithKind("NodeMetricsList"), query, provider)
+		if err != nil {
+			return "", err
+		}
+		var allocatable float64
+		for _, item := range list.Items {
+			value, err := getUsage(item.Object, resourceName)
+			if err != nil {
+				return "", err
+			}
+			usage += value
+			if query.Utilization {
+				node := &corev1.Node{}
+				if err := k.k8sClient.Get(ctx, types.NamespacedName{Name: item.GetName()}, node); err != nil {
+					return "", err
+				}
+				quantity := node.Status.Allocatable[resourceName]
+				allocatable += quantity.AsApproximateFloat64()
+			}
+		}
+		if query.Utilization {
+			if allocatable == 0 {
+				return "", errors.New("no allocatable resources found for the nodes")
+			}
+			usage = usage / allocatable * 100
+		}
+	default:
+		return "", fmt.Errorf("kind %q not supported by metrics queries, use Pod or Node", query.Kind)
+	}
+	return strconv.FormatFloat(usage, 'f', -1, 64), nil
+}
+
+func (k *KeptnKubernetesProvider) listResources(ctx context.Context, gvk schema.GroupVersionKind, query *klcv1alpha2.KubernetesQuery, provider klcv1alpha2.KeptnEvaluationProvider) (*unstructured.UnstructuredList, error) {
+	namespace, err := getNamespace(ctx, query, provider)
+	if err != nil {
+		return nil, err
+	}
+	opts := []client.ListOption{client.InNamespace(namespace)}
+	if query.LabelSelector != "" {
+		selector, err := labels.Parse(query.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %s: %w", query.LabelSelector, err)
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+	if query.FieldSelector != "" {
+		selector, err := fields.ParseSelector(query.FieldSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid field selector %s: %w", query.FieldSelector, err)
+		}
+		opts = append(opts, client.MatchingFieldsSelector{Selector: selector})
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk)
+	if err := k.k8sClient.List(ctx, list, opts...); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// getUsage parses the usage of the resource reported in the usage property of the metrics object
+func getUsage(obj map[string]interface{}, resourceName corev1.ResourceName) (float64, error) {
+	value, found, err := unstructured.NestedString(obj, "usage", string(resourceName))
+	if err != nil || !found {
+		return 0, fmt.Errorf("no %s usage found in metrics", resourceName)
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s usage %s: %w", resourceName, value, err)
+	}
+	return quantity.AsApproximateFloat64(), nil
+}
+
+func getGroupVersion(query *klcv1alpha2.KubernetesQuery) (schema.GroupVersion, error) {
+	if query.APIVersion == "" {
+		return corev1.SchemeGroupVersion, nil
+	}
+	gv, err := schema.ParseGroupVersion(query.APIVersion)
+	if err != nil {
+		return schema.GroupVersion{}, fmt.Errorf("invalid apiVersion %s: %w", query.APIVersion, err)
+	}
+	return gv, nil
+}
+
+// CheckKubernetesKind returns an error if the query reads objects of a sensitive kind
+func CheckKubernetesKind(query *klcv1alpha2.KubernetesQuery) error {
+	gv, err := getGroupVersion(query)
+	if err != nil {
+		return err
+	}
+	for _, kind := range sensitiveKinds {
+		if gv.Group == kind.Group && strings.EqualFold(strings.TrimSpace(query.Kind), kind.Kind) {
+			return fmt.Errorf("%w: %s", controllererrors.ErrSensitiveKindNotAllowed, query.Kind)
+		}
+	}
+	return nil
+}
+
+// getNamespace returns the namespace of the evaluation, the namespace of the query may only repeat it
+func getNamespace(ctx context.Context, query *klcv1alpha2.KubernetesQuery, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	namespace := evaluationNamespace(ctx, provider)
+	if query.Namespace != "" && query.Namespace != namespace {
+		return "", controllererrors.ErrKubernetesNamespaceNotAllowed
+	}
+	return namespace, nil
+}
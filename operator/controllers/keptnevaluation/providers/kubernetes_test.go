@@ -0,0 +1,253 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newTestPod(name string, namespace string, app string, waitingReason string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"app": app},
+		},
+	}
+	if waitingReason != "" {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				Name: "main",
+				State: corev1.ContainerState{
+					Waiting: &corev1.ContainerStateWaiting{Reason: waitingReason},
+				},
+			},
+		}
+	}
+	return pod
+}
+
+func newTestMetrics(kind string, name string, namespace string, usage map[string]interface{}) *unstructured.Unstructured {
+	metrics := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	metrics.SetGroupVersionKind(metricsGroupVersion.WithKind(kind))
+	metrics.SetName(name)
+	metrics.SetNamespace(namespace)
+	if kind == "PodMetrics" {
+		metrics.Object["containers"] = []interface{}{
+			map[string]interface{}{"name": "main", "usage": usage},
+			map[string]interface{}{"name": "sidecar", "usage": usage},
+		}
+	} else {
+		metrics.Object["usage"] = usage
+	}
+	return metrics
+}
+
+func TestKubernetesProvider(t *testing.T) {
+	replicas := int32(3)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "podtato-head",
+			Namespace: "default",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-1",
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
+	}
+	objs := []client.Object{
+		newTestPod("running", "default", "podtato-head", ""),
+		newTestPod("crashing", "default", "podtato-head", "CrashLoopBackOff"),
+		newTestPod("pulling", "default", "podtato-head", "ImagePullBackOff"),
+		newTestPod("other-app", "default", "other", "CrashLoopBackOff"),
+		newTestPod("other-namespace", "other", "podtato-head", "CrashLoopBackOff"),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "default"},
+			Data:       map[string][]byte{"value": []byte("42")},
+		},
+		deployment,
+		node,
+		newTestMetrics("PodMetrics", "running", "default", map[string]interface{}{"cpu": "250m", "memory": "64Mi"}),
+		newTestMetrics("NodeMetrics", "node-1", "", map[string]interface{}{"cpu": "1", "memory": "2Gi"}),
+	}
+	fakeClient, err := fake.NewClient(objs...)
+	require.Nil(t, err)
+
+	kkp := KeptnKubernetesProvider{
+		Log:       ctrl.Log.WithName("testytest"),
+		k8sClient: fakeClient,
+	}
+	provider := klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		k8s    *klcv1alpha2.KubernetesQuery
+		result string
+		err    bool
+	}{
+		{
+			name:   "count pods with label",
+			query:  "count",
+			k8s:    &klcv1alpha2.KubernetesQuery{Kind: "Pod", LabelSelector: "app=podtato-head"},
+			result: "3",
+		},
+		{
+			name:  "count crashing pods",
+			query: "count",
+			k8s: &klcv1alpha2.KubernetesQuery{
+				Kind:          "Pod",
+				LabelSelector: "app=podtato-head",
+				JSONPath:      `{.status.containerStatuses[?(@.state.waiting.reason=="CrashLoopBackOff")]}`,
+			},
+			result: "1",
+		},
+		{
+			name:   "count pods in the namespace of the evaluation",
+			query:  "count",
+			k8s:    &klcv1alpha2.KubernetesQuery{Kind: "Pod", Namespace: "default"},
+			result: "4",
+		},
+		{
+			name:  "count pods in other namespace",
+			query: "count",
+			k8s:   &klcv1alpha2.KubernetesQuery{Kind: "Pod", Namespace: "other"},
+			err:   true,
+		},
+		{
+			name:  "count secrets",
+			query: "count",
+			k8s:   &klcv1alpha2.KubernetesQuery{APIVersion: "v1", Kind: "Secret"},
+			err:   true,
+		},
+		{
+			name:  "read field of secret",
+			query: "field",
+			k8s:   &klcv1alpha2.KubernetesQuery{Kind: "secret", Name: "token", JSONPath: ".data.value"},
+			err:   true,
+		},
+		{
+			name:  "read field",
+			query: "field",
+			k8s: &klcv1alpha2.KubernetesQuery{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "podtato-head",
+				JSONPath:   ".spec.replicas",
+			},
+			result: "3",
+		},
+		{
+			name:  "read field of missing resource",
+			query: "field",
+			k8s: &klcv1alpha2.KubernetesQuery{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "unknown",
+				JSONPath:   ".spec.replicas",
+			},
+			err: true,
+		},
+		{
+			name:  "read field without name",
+			query: "field",
+			k8s:   &klcv1alpha2.KubernetesQuery{Kind: "Pod", JSONPath: ".spec.priority"},
+			err:   true,
+		},
+		{
+			name:   "pod cpu usage",
+			query:  "metrics",
+			k8s:    &klcv1alpha2.KubernetesQuery{Kind: "Pod", Resource: "cpu"},
+			result: "0.5",
+		},
+		{
+			name:   "pod memory usage",
+			query:  "metrics",
+			k8s:    &klcv1alpha2.KubernetesQuery{Kind: "Pod", Resource: "memory"},
+			result: "134217728",
+		},
+		{
+			name:   "node cpu utilization",
+			query:  "metrics",
+			k8s:    &klcv1alpha2.KubernetesQuery{Kind: "Node", Utilization: true},
+			result: "25",
+		},
+		{
+			name:  "unsupported metrics kind",
+			query: "metrics",
+			k8s:   &klcv1alpha2.KubernetesQuery{Kind: "Deployment"},
+			err:   true,
+		},
+		{
+			name:  "unsupported query type",
+			query: "sum",
+			k8s:   &klcv1alpha2.KubernetesQuery{Kind: "Pod"},
+			err:   true,
+		},
+		{
+			name:  "missing kubernetes property",
+			query: "count",
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := provider
+			if tt.k8s != nil && tt.k8s.Kind == "Node" {
+				// nodes are cluster scoped
+				p.Namespace = ""
+			}
+			r, e := kkp.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: tt.query, Kubernetes: tt.k8s}, p)
+			if tt.err {
+				require.NotNil(t, e)
+				return
+			}
+			require.Nil(t, e)
+			require.Equal(t, tt.result, r)
+		})
+	}
+}
+
+func TestKubernetesProvider_ErrorsOmitValues(t *testing.T) {
+	fakeClient, err := fake.NewClient(newTestPod("running", "default", "podtato-head", ""))
+	require.Nil(t, err)
+	kkp := KeptnKubernetesProvider{
+		Log:       ctrl.Log.WithName("testytest"),
+		k8sClient: fakeClient,
+	}
+	provider := klcv1alpha2.KeptnEvaluationProvider{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}}
+	objective := klcv1alpha2.Objective{
+		Query:      "field",
+		Kubernetes: &klcv1alpha2.KubernetesQuery{Kind: "Pod", Name: "running", JSONPath: ".metadata.labels.app"},
+	}
+
+	_, err = kkp.EvaluateQuery(context.TODO(), objective, provider)
+	require.ErrorContains(t, err, ".metadata.labels.app")
+	require.ErrorContains(t, err, "string")
+	require.NotContains(t, err.Error(), "podtato-head")
+}
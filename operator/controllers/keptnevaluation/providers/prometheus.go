@@ -9,25 +9,70 @@ import (
 	prometheus "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 	"net/http" //nolint:gci
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
 	"time"
 )
 
 type KeptnPrometheusProvider struct {
 	Log        logr.Logger
 	httpClient http.Client
+	k8sClient  client.Client
 }
 
 func (r *KeptnPrometheusProvider) EvaluateQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	series, err := r.EvaluateSeriesQuery(ctx, objective, provider)
+	if err != nil {
+		return "", err
+	}
+
+	// We are only allowed to return one value, if not the query may be malformed
+	if len(series) > 1 {
+		if objective.Range != nil {
+			r.Log.Info("Too many series in the query result")
+			return "", fmt.Errorf("too many series in the query result")
+		}
+		r.Log.Info("Too many values in the query result")
+		return "", fmt.Errorf("too many values in the query result")
+	}
+	return series[0].Value, nil
+}
+
+// EvaluateSeriesQuery returns every series of the query result. For range queries,
+// the values of every series are reduced to a single value with the aggregation of the objective.
+func (r *KeptnPrometheusProvider) EvaluateSeriesQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) ([]Series, error) {
+	ctx, cancel := withQueryTimeout(ctx)
 	defer cancel()
 
-	queryTime := time.Now().UTC()
-	r.Log.Info("Running query: /api/v1/query?query=" + objective.Query + "&time=" + queryTime.String())
-	client, err := promapi.NewClient(promapi.Config{Address: provider.Spec.TargetServer, Client: &r.httpClient})
+	httpClient, err := newProviderHTTPClient(ctx, r.k8sClient, r.httpClient, provider)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	client, err := promapi.NewClient(promapi.Config{Address: provider.Spec.TargetServer, Client: httpClient})
+	if err != nil {
+		return nil, err
 	}
 	api := prometheus.NewAPI(client)
+
+	var series []Series
+	if objective.Range != nil {
+		series, err = r.evaluateRangeQuery(ctx, api, objective)
+	} else {
+		series, err = r.evaluateInstantQuery(ctx, api, objective)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(series) == 0 {
+		r.Log.Info("No values in query result")
+		return nil, fmt.Errorf("no values in query result")
+	}
+	return series, nil
+}
+
+func (r *KeptnPrometheusProvider) evaluateInstantQuery(ctx context.Context, api prometheus.API, objective klcv1alpha2.Objective) ([]Series, error) {
+	queryTime := time.Now().UTC()
+	r.Log.Info("Running query: /api/v1/query?query=" + objective.Query + "&time=" + queryTime.String())
 	result, w, err := api.Query(
 		ctx,
 		objective.Query,
@@ -36,7 +81,7 @@ func (r *KeptnPrometheusProvider) EvaluateQuery(ctx context.Context, objective k
 	)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if len(w) != 0 {
@@ -46,18 +91,145 @@ func (r *KeptnPrometheusProvider) EvaluateQuery(ctx context.Context, objective k
 	// check if we can cast the result to a vector, it might be another data struct which we can't process
 	resultVector, ok := result.(model.Vector)
 	if !ok {
-		return "", fmt.Errorf("could not cast result")
+		return nil, fmt.Errorf("could not cast result")
 	}
 
-	// We are only allowed to return one value, if not the query may be malformed
-	// we are using two different errors to give the user more information about the result
-	if len(resultVector) == 0 {
-		r.Log.Info("No values in query result")
-		return "", fmt.Errorf("no values in query result")
-	} else if len(resultVector) > 1 {
-		r.Log.Info("Too many values in the query result")
-		return "", fmt.Errorf("too many values in the query result")
+	series := make([]Series, 0, len(resultVector))
+	for _, sample := range resultVector {
+		series = append(series, Series{
+			Labels: getSeriesLabels(sample.Metric),
+			Value:  sample.Value.String(),
+		})
+	}
+	return series, nil
+}
+
+// evaluateRangeQuery runs the query over the time window of the objective and
+// reduces the values of every resulting series to a single value with the aggregation of the objective
+func (r *KeptnPrometheusProvider) evaluateRangeQuery(ctx context.Context, api prometheus.API, objective klcv1alpha2.Objective) ([]Series, error) {
+	queryRange := prometheus.Range{
+		End:  time.Now().UTC(),
+		Step: objective.GetRangeStep(),
+	}
+	queryRange.Start = queryRange.End.Add(-objective.GetRangeInterval())
+	r.Log.Info("Running query: /api/v1/query_range?query=" + objective.Query + "&start=" + queryRange.Start.String() + "&end=" + queryRange.End.String() + "&step=" + queryRange.Step.String())
+
+	result, w, err := api.QueryRange(ctx, objective.Query, queryRange)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(w) != 0 {
+		r.Log.Info("Prometheus API returned warnings: " + w[0])
+	}
+
+	resultMatrix, ok := result.(model.Matrix)
+	if !ok {
+		return nil, fmt.Errorf("could not cast result")
+	}
+
+	series := make([]Series, 0, len(resultMatrix))
+	for _, stream := range resultMatrix {
+		// series without values in the time window are skipped
+		if len(stream.Values) == 0 {
+			continue
+		}
+		values := make([]float64, 0, len(stream.Values))
+		for _, point := range stream.Values {
+			values = append(values, float64(point.Value))
+		}
+		value, err := aggregate(values, objective.GetRangeAggregation())
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, Series{
+			Labels: getSeriesLabels(stream.Metric),
+			Value:  strconv.FormatFloat(value, 'f', -1, 64),
+		})
+	}
+	return series, nil
+}
+
+// EvaluateBaselineQuery runs the query over the lookback window of the anomaly detection of the objective
+// and returns the values of the single resulting series. The window ends at the start of the evaluation,
+// so that the baseline is not affected by the changes being evaluated.
+func (r *KeptnPrometheusProvider) EvaluateBaselineQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) ([]float64, error) {
+	if objective.Anomaly == nil {
+		return nil, fmt.Errorf("objective %s does not detect anomalies", objective.Name)
+	}
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	httpClient, err := newProviderHTTPClient(ctx, r.k8sClient, r.httpClient, provider)
+	if err != nil {
+		return nil, err
+	}
+	client, err := promapi.NewClient(promapi.Config{Address: provider.Spec.TargetServer, Client: httpClient})
+	if err != nil {
+		return nil, err
+	}
+
+	queryRange := prometheus.Range{
+		End:  PhaseStartFromContext(ctx).UTC(),
+		Step: objective.Anomaly.GetStep(),
+	}
+	if queryRange.End.IsZero() {
+		queryRange.End = time.Now().UTC()
+	}
+	queryRange.Start = queryRange.End.Add(-objective.Anomaly.GetLookback())
+	r.Log.Info("Running baseline query: /api/v1/query_range?query=" + objective.Query + "&start=" + queryRange.Start.String() + "&end=" + queryRange.End.String() + "&step=" + queryRange.Step.String())
+
+	result, w, err := prometheus.NewAPI(client).QueryRange(ctx, objective.Query, queryRange)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(w) != 0 {
+		r.Log.Info("Prometheus API returned warnings: " + w[0])
+	}
+
+	resultMatrix, ok := result.(model.Matrix)
+	if !ok {
+		return nil, fmt.Errorf("could not cast result")
+	}
+	if len(resultMatrix) > 1 {
+		return nil, fmt.Errorf("too many series in the baseline query result")
+	}
+	if len(resultMatrix) == 0 || len(resultMatrix[0].Values) == 0 {
+		return nil, fmt.Errorf("no values in the baseline query result")
+	}
+
+	values := make([]float64, 0, len(resultMatrix[0].Values))
+	for _, point := range resultMatrix[0].Values {
+		values = append(values, float64(point.Value))
+	}
+	return values, nil
+}
+
+func getSeriesLabels(metric model.Metric) map[string]string {
+	if len(metric) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(metric))
+	for name, value := range metric {
+		labels[string(name)] = string(value)
+	}
+	return labels
+}
+
+// CheckHealth runs a trivial instant query to check that the target server is reachable with the configured credentials
+func (r *KeptnPrometheusProvider) CheckHealth(ctx context.Context, provider klcv1alpha2.KeptnEvaluationProvider) error {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	httpClient, err := newProviderHTTPClient(ctx, r.k8sClient, r.httpClient, provider)
+	if err != nil {
+		return err
+	}
+	client, err := promapi.NewClient(promapi.Config{Address: provider.Spec.TargetServer, Client: httpClient})
+	if err != nil {
+		return err
 	}
-	value := resultVector[0].Value.String()
-	return value, nil
+	_, _, err = prometheus.NewAPI(client).Query(ctx, "vector(1)", time.Now().UTC())
+	return err
 }
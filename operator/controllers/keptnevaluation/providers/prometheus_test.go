@@ -5,11 +5,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const promWarnPayload = "{\"status\":\"success\",\"warnings\":[\"awarning\"],\"data\":{\"resultType\":\"vector\",\"result\":[{\"metric\":{\"__name__\":\"kube_pod_info\",\"container\":\"kube-rbac-proxy-main\",\"created_by_kind\":\"DaemonSet\",\"created_by_name\":\"kindnet\",\"host_ip\":\"172.18.0.2\",\"host_network\":\"true\",\"instance\":\"10.244.0.24:8443\",\"job\":\"kube-state-metrics\",\"namespace\":\"kube-system\",\"node\":\"kind-control-plane\",\"pod\":\"kindnet-llt85\",\"pod_ip\":\"172.18.0.2\",\"uid\":\"0bb9d9db-2658-439f-aed9-ab3e8502397d\"},\"value\":[1669714193.275,\"1\"]}]}}"
@@ -18,6 +22,22 @@ const promEmptyDataPayload = "{\"status\":\"success\",\"data\":{\"resultType\":\
 const promMatrixPayload = "{\"status\":\"success\",\"data\":{\"resultType\":\"matrix\",\"result\":[]}}"
 const promMultiPointPayload = "{\"status\":\"success\",\"data\":{\"resultType\":\"vector\",\"result\":[{\"metric\":{\"__name__\":\"kube_pod_info\",\"container\":\"kube-rbac-proxy-main\",\"created_by_kind\":\"DaemonSet\",\"created_by_name\":\"kindnet\",\"host_ip\":\"172.18.0.2\",\"host_network\":\"true\",\"instance\":\"10.244.0.24:8443\",\"job\":\"kube-state-metrics\",\"namespace\":\"kube-system\",\"node\":\"kind-control-plane\",\"pod\":\"kindnet-llt85\",\"pod_ip\":\"172.18.0.2\",\"uid\":\"0bb9d9db-2658-439f-aed9-ab3e8502397d\"},\"value\":[1669714193.275,\"1\"]},{\"metric\":{\"__name__\":\"kube_pod_info\",\"container\":\"kube-rbac-proxy-main\",\"created_by_kind\":\"DaemonSet\",\"created_by_name\":\"kube-proxy\",\"host_ip\":\"172.18.0.2\",\"host_network\":\"true\",\"instance\":\"10.244.0.24:8443\",\"job\":\"kube-state-metrics\",\"namespace\":\"kube-system\",\"node\":\"kind-control-plane\",\"pod\":\"kube-proxy-dlq7m\",\"pod_ip\":\"172.18.0.2\",\"priority_class\":\"system-node-critical\",\"uid\":\"31240e57-5286-4bc6-ad69-80b68bf806d0\"},\"value\":[1669714193.275,\"1\"]},{\"metric\":{\"__name__\":\"kube_pod_info\",\"container\":\"kube-rbac-proxy-main\",\"created_by_kind\":\"DaemonSet\",\"created_by_name\":\"node-exporter\",\"host_ip\":\"172.18.0.2\",\"host_network\":\"true\",\"instance\":\"10.244.0.24:8443\",\"job\":\"kube-state-metrics\",\"namespace\":\"monitoring\",\"node\":\"kind-control-plane\",\"pod\":\"node-exporter-dv6nr\",\"pod_ip\":\"172.18.0.2\",\"priority_class\":\"system-cluster-critical\",\"uid\":\"cf7baf10-ac9a-4b7d-9510-a6502d7ed271\"},\"value\":[1669714193.275,\"1\"]}]}}"
 
+// newPrometheusTestClient returns a client holding the token referenced by the test providers
+func newPrometheusTestClient(t *testing.T) client.Client {
+	apiToken := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myapitoken",
+			Namespace: "",
+		},
+		Data: map[string][]byte{
+			"mykey": []byte("mytoken"),
+		},
+	}
+	fakeClient, err := fake.NewClient(apiToken)
+	require.Nil(t, err)
+	return fakeClient
+}
+
 func TestHandlingWrongData(t *testing.T) {
 	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		_, err := w.Write([]byte("garbage"))
@@ -28,6 +48,7 @@ func TestHandlingWrongData(t *testing.T) {
 	kpp := KeptnPrometheusProvider{
 		httpClient: http.Client{},
 		Log:        ctrl.Log.WithName("testytest"),
+		k8sClient:  newPrometheusTestClient(t),
 	}
 	obj := klcv1alpha2.Objective{
 		Query: "garbage",
@@ -58,6 +79,7 @@ func TestHandlingWarnings(t *testing.T) {
 	kpp := KeptnPrometheusProvider{
 		httpClient: http.Client{},
 		Log:        ctrl.Log.WithName("testytest"),
+		k8sClient:  newPrometheusTestClient(t),
 	}
 	obj := klcv1alpha2.Objective{
 		Query: "garbage",
@@ -88,6 +110,7 @@ func TestHandlingMultiDatapoint(t *testing.T) {
 	kpp := KeptnPrometheusProvider{
 		httpClient: http.Client{},
 		Log:        ctrl.Log.WithName("testytest"),
+		k8sClient:  newPrometheusTestClient(t),
 	}
 	obj := klcv1alpha2.Objective{
 		Query: "garbage",
@@ -118,6 +141,7 @@ func TestHandlingEmptyDatapoint(t *testing.T) {
 	kpp := KeptnPrometheusProvider{
 		httpClient: http.Client{},
 		Log:        ctrl.Log.WithName("testytest"),
+		k8sClient:  newPrometheusTestClient(t),
 	}
 	obj := klcv1alpha2.Objective{
 		Query: "garbage",
@@ -148,6 +172,7 @@ func TestHandlingUnsupportedAnswerType(t *testing.T) {
 	kpp := KeptnPrometheusProvider{
 		httpClient: http.Client{},
 		Log:        ctrl.Log.WithName("testytest"),
+		k8sClient:  newPrometheusTestClient(t),
 	}
 	obj := klcv1alpha2.Objective{
 		Query: "garbage",
@@ -178,6 +203,7 @@ func TestHappyPath(t *testing.T) {
 	kpp := KeptnPrometheusProvider{
 		httpClient: http.Client{},
 		Log:        ctrl.Log.WithName("testytest"),
+		k8sClient:  newPrometheusTestClient(t),
 	}
 	obj := klcv1alpha2.Objective{
 		Query: "garbage",
@@ -197,3 +223,227 @@ func TestHappyPath(t *testing.T) {
 	require.Equal(t, "1", r)
 	require.Nil(t, e)
 }
+
+func TestRangeQuery(t *testing.T) {
+	const promRangePayload = "{\"status\":\"success\",\"data\":{\"resultType\":\"matrix\",\"result\":[{\"metric\":{\"__name__\":\"http_request_duration\"},\"values\":[[1669714193.275,\"1\"],[1669714253.275,\"5\"],[1669714313.275,\"3\"]]}]}}"
+	const promRangeMultiSeriesPayload = "{\"status\":\"success\",\"data\":{\"resultType\":\"matrix\",\"result\":[{\"metric\":{\"pod\":\"a\"},\"values\":[[1669714193.275,\"1\"]]},{\"metric\":{\"pod\":\"b\"},\"values\":[[1669714193.275,\"2\"]]}]}}"
+
+	tests := []struct {
+		name        string
+		payload     string
+		aggregation klcv1alpha2.RangeAggregation
+		result      string
+		err         bool
+	}{
+		{
+			name:    "avg",
+			payload: promRangePayload,
+			result:  "3",
+		},
+		{
+			name:        "max",
+			payload:     promRangePayload,
+			aggregation: klcv1alpha2.RangeAggregationMax,
+			result:      "5",
+		},
+		{
+			name:        "last",
+			payload:     promRangePayload,
+			aggregation: klcv1alpha2.RangeAggregationLast,
+			result:      "3",
+		},
+		{
+			name:    "empty matrix",
+			payload: promMatrixPayload,
+			err:     true,
+		},
+		{
+			name:    "multiple series",
+			payload: promRangeMultiSeriesPayload,
+			err:     true,
+		},
+		{
+			name:    "vector",
+			payload: promPayload,
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/api/v1/query_range", r.URL.Path)
+				require.Nil(t, r.ParseForm())
+				require.Equal(t, "120", r.Form.Get("step"))
+				_, err := w.Write([]byte(tt.payload))
+				require.Nil(t, err)
+			}))
+			defer svr.Close()
+
+			kpp := KeptnPrometheusProvider{
+				httpClient: http.Client{},
+				Log:        ctrl.Log.WithName("testytest"),
+				k8sClient:  newPrometheusTestClient(t),
+			}
+			obj := klcv1alpha2.Objective{
+				Query: "http_request_duration",
+				Range: &klcv1alpha2.QueryRange{
+					Interval:    metav1.Duration{Duration: 10 * time.Minute},
+					Step:        metav1.Duration{Duration: 2 * time.Minute},
+					Aggregation: tt.aggregation,
+				},
+			}
+			p := klcv1alpha2.KeptnEvaluationProvider{
+				Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+					TargetServer: svr.URL,
+				},
+			}
+			r, e := kpp.EvaluateQuery(context.TODO(), obj, p)
+			if tt.err {
+				require.NotNil(t, e)
+				return
+			}
+			require.Nil(t, e)
+			require.Equal(t, tt.result, r)
+		})
+	}
+}
+
+func TestBaselineQuery(t *testing.T) {
+	const promRangePayload = "{\"status\":\"success\",\"data\":{\"resultType\":\"matrix\",\"result\":[{\"metric\":{\"__name__\":\"http_request_duration\"},\"values\":[[1669714193.275,\"1\"],[1669714253.275,\"5\"],[1669714313.275,\"3\"]]}]}}"
+	const promRangeMultiSeriesPayload = "{\"status\":\"success\",\"data\":{\"resultType\":\"matrix\",\"result\":[{\"metric\":{\"pod\":\"a\"},\"values\":[[1669714193.275,\"1\"]]},{\"metric\":{\"pod\":\"b\"},\"values\":[[1669714193.275,\"2\"]]}]}}"
+	phaseStart := time.Date(2022, 11, 29, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		payload string
+		values  []float64
+		err     bool
+	}{
+		{
+			name:    "single series",
+			payload: promRangePayload,
+			values:  []float64{1, 5, 3},
+		},
+		{
+			name:    "empty matrix",
+			payload: promMatrixPayload,
+			err:     true,
+		},
+		{
+			name:    "multiple series",
+			payload: promRangeMultiSeriesPayload,
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/api/v1/query_range", r.URL.Path)
+				require.Nil(t, r.ParseForm())
+				require.Equal(t, "300", r.Form.Get("step"))
+				require.Equal(t, "1669708800", r.Form.Get("start"))
+				require.Equal(t, "1669716000", r.Form.Get("end"))
+				_, err := w.Write([]byte(tt.payload))
+				require.Nil(t, err)
+			}))
+			defer svr.Close()
+
+			kpp := KeptnPrometheusProvider{
+				httpClient: http.Client{},
+				Log:        ctrl.Log.WithName("testytest"),
+				k8sClient:  newPrometheusTestClient(t),
+			}
+			obj := klcv1alpha2.Objective{
+				Query: "http_request_duration",
+				Anomaly: &klcv1alpha2.AnomalyDetection{
+					Lookback: metav1.Duration{Duration: 2 * time.Hour},
+					Step:     metav1.Duration{Duration: 5 * time.Minute},
+				},
+			}
+			p := klcv1alpha2.KeptnEvaluationProvider{
+				Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+					TargetServer: svr.URL,
+				},
+			}
+			values, err := kpp.EvaluateBaselineQuery(ContextWithPhaseStart(context.TODO(), phaseStart), obj, p)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.values, values)
+		})
+	}
+}
+
+func TestSeriesQuery(t *testing.T) {
+	const promRangeMultiSeriesPayload = "{\"status\":\"success\",\"data\":{\"resultType\":\"matrix\",\"result\":[{\"metric\":{\"pod\":\"a\"},\"values\":[[1669714193.275,\"1\"],[1669714253.275,\"3\"]]},{\"metric\":{\"pod\":\"b\"},\"values\":[[1669714193.275,\"2\"]]},{\"metric\":{\"pod\":\"c\"},\"values\":[]}]}}"
+
+	tests := []struct {
+		name    string
+		payload string
+		rng     *klcv1alpha2.QueryRange
+		series  []Series
+		err     bool
+	}{
+		{
+			name:    "vector",
+			payload: promMultiPointPayload,
+			series: []Series{
+				{Labels: map[string]string{"__name__": "kube_pod_info", "container": "kube-rbac-proxy-main", "created_by_kind": "DaemonSet", "created_by_name": "kindnet", "host_ip": "172.18.0.2", "host_network": "true", "instance": "10.244.0.24:8443", "job": "kube-state-metrics", "namespace": "kube-system", "node": "kind-control-plane", "pod": "kindnet-llt85", "pod_ip": "172.18.0.2", "uid": "0bb9d9db-2658-439f-aed9-ab3e8502397d"}, Value: "1"},
+				{Labels: map[string]string{"__name__": "kube_pod_info", "container": "kube-rbac-proxy-main", "created_by_kind": "DaemonSet", "created_by_name": "kube-proxy", "host_ip": "172.18.0.2", "host_network": "true", "instance": "10.244.0.24:8443", "job": "kube-state-metrics", "namespace": "kube-system", "node": "kind-control-plane", "pod": "kube-proxy-dlq7m", "pod_ip": "172.18.0.2", "priority_class": "system-node-critical", "uid": "31240e57-5286-4bc6-ad69-80b68bf806d0"}, Value: "1"},
+				{Labels: map[string]string{"__name__": "kube_pod_info", "container": "kube-rbac-proxy-main", "created_by_kind": "DaemonSet", "created_by_name": "node-exporter", "host_ip": "172.18.0.2", "host_network": "true", "instance": "10.244.0.24:8443", "job": "kube-state-metrics", "namespace": "monitoring", "node": "kind-control-plane", "pod": "node-exporter-dv6nr", "pod_ip": "172.18.0.2", "priority_class": "system-cluster-critical", "uid": "cf7baf10-ac9a-4b7d-9510-a6502d7ed271"}, Value: "1"},
+			},
+		},
+		{
+			name:    "matrix",
+			payload: promRangeMultiSeriesPayload,
+			rng: &klcv1alpha2.QueryRange{
+				Interval: metav1.Duration{Duration: 10 * time.Minute},
+			},
+			series: []Series{
+				{Labels: map[string]string{"pod": "a"}, Value: "2"},
+				{Labels: map[string]string{"pod": "b"}, Value: "2"},
+			},
+		},
+		{
+			name:    "empty vector",
+			payload: promEmptyDataPayload,
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, err := w.Write([]byte(tt.payload))
+				require.Nil(t, err)
+			}))
+			defer svr.Close()
+
+			kpp := KeptnPrometheusProvider{
+				httpClient: http.Client{},
+				Log:        ctrl.Log.WithName("testytest"),
+				k8sClient:  newPrometheusTestClient(t),
+			}
+			obj := klcv1alpha2.Objective{
+				Query: "kube_pod_info",
+				Range: tt.rng,
+			}
+			p := klcv1alpha2.KeptnEvaluationProvider{
+				Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+					TargetServer: svr.URL,
+				},
+			}
+			series, e := kpp.EvaluateSeriesQuery(context.TODO(), obj, p)
+			if tt.err {
+				require.NotNil(t, e)
+				return
+			}
+			require.Nil(t, e)
+			require.Equal(t, tt.series, series)
+		})
+	}
+}
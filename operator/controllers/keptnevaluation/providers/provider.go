@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
@@ -16,6 +17,40 @@ type KeptnSLIProvider interface {
 	EvaluateQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error)
 }
 
+// Series is a single series of a query result
+type Series struct {
+	Labels map[string]string
+	Value  string
+}
+
+// KeptnSLIProviderMultiSeries is implemented by providers that can return every series of a query result
+type KeptnSLIProviderMultiSeries interface {
+	EvaluateSeriesQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) ([]Series, error)
+}
+
+// KeptnSLIProviderBaseline is implemented by providers that can return the values of a query over the lookback
+// window of the anomaly detection of an objective, which ends at the start of the evaluation
+type KeptnSLIProviderBaseline interface {
+	EvaluateBaselineQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) ([]float64, error)
+}
+
+// KeptnSLIProviderHealthChecker is implemented by providers that can check the health of their target server
+type KeptnSLIProviderHealthChecker interface {
+	CheckHealth(ctx context.Context, provider klcv1alpha2.KeptnEvaluationProvider) error
+}
+
+// DefaultQueryTimeout is the timeout of queries that are not limited by the context
+const DefaultQueryTimeout = 20 * time.Second
+
+// withQueryTimeout applies the DefaultQueryTimeout unless the context already has a deadline,
+// e.g. the timeout configured for the objective
+func withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, DefaultQueryTimeout)
+}
+
 // NewProvider is a factory method that chooses the right implementation of KeptnSLIProvider
 func NewProvider(provider string, log logr.Logger, k8sClient client.Client) (KeptnSLIProvider, error) {
 	switch strings.ToLower(provider) {
@@ -23,6 +58,7 @@ func NewProvider(provider string, log logr.Logger, k8sClient client.Client) (Kep
 		return &KeptnPrometheusProvider{
 			httpClient: http.Client{},
 			Log:        log,
+			k8sClient:  k8sClient,
 		}, nil
 	case "dynatrace":
 		return &KeptnDynatraceProvider{
@@ -30,7 +66,46 @@ func NewProvider(provider string, log logr.Logger, k8sClient client.Client) (Kep
 			Log:        log,
 			k8sClient:  k8sClient,
 		}, nil
+	case "datadog":
+		return &KeptnDatadogProvider{
+			httpClient: http.Client{},
+			Log:        log,
+			k8sClient:  k8sClient,
+		}, nil
+	case "kubernetes":
+		return &KeptnKubernetesProvider{
+			Log:       log,
+			k8sClient: k8sClient,
+		}, nil
+	case "grpc":
+		return &KeptnGRPCProvider{
+			Log:       log,
+			k8sClient: k8sClient,
+		}, nil
+	case "http":
+		return &KeptnHTTPProvider{
+			httpClient: http.Client{},
+			Log:        log,
+			k8sClient:  k8sClient,
+		}, nil
+	case "static":
+		return &KeptnStaticProvider{
+			Log:       log,
+			k8sClient: k8sClient,
+		}, nil
+	case "alertmanager":
+		return &KeptnAlertmanagerProvider{
+			httpClient: http.Client{},
+			Log:        log,
+			k8sClient:  k8sClient,
+		}, nil
 	default:
 		return nil, fmt.Errorf("provider %s not supported", provider)
 	}
 }
+
+// IsSupported returns true if NewProvider supports the given type of provider
+func IsSupported(provider string) bool {
+	_, err := NewProvider(provider, logr.Discard(), nil)
+	return err == nil
+}
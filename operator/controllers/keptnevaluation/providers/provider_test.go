@@ -1,10 +1,20 @@
 package providers
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 func TestFactory(t *testing.T) {
@@ -23,6 +33,36 @@ func TestFactory(t *testing.T) {
 			provider: &KeptnDynatraceProvider{},
 			err:      false,
 		},
+		{
+			name:     "datadog",
+			provider: &KeptnDatadogProvider{},
+			err:      false,
+		},
+		{
+			name:     "kubernetes",
+			provider: &KeptnKubernetesProvider{},
+			err:      false,
+		},
+		{
+			name:     "grpc",
+			provider: &KeptnGRPCProvider{},
+			err:      false,
+		},
+		{
+			name:     "http",
+			provider: &KeptnHTTPProvider{},
+			err:      false,
+		},
+		{
+			name:     "static",
+			provider: &KeptnStaticProvider{},
+			err:      false,
+		},
+		{
+			name:     "alertmanager",
+			provider: &KeptnAlertmanagerProvider{},
+			err:      false,
+		},
 		{
 			name:     "invalid",
 			provider: nil,
@@ -41,3 +81,125 @@ func TestFactory(t *testing.T) {
 
 	}
 }
+
+func TestCheckHealth(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "credentials",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"token":       []byte("mytoken"),
+			DatadogAPIKey: []byte("apikey"),
+			DatadogAppKey: []byte("appkey"),
+		},
+	}
+	fakeClient, err := fake.NewClient(secret)
+	require.Nil(t, err)
+	secretKeyRef := corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"},
+		Key:                  "token",
+	}
+
+	tests := []struct {
+		providerType string
+		path         string
+		statusCode   int
+		payload      string
+		err          bool
+	}{
+		{
+			providerType: "prometheus",
+			path:         "/api/v1/query",
+			statusCode:   http.StatusOK,
+			payload:      promPayload,
+		},
+		{
+			providerType: "prometheus",
+			path:         "/api/v1/query",
+			statusCode:   http.StatusServiceUnavailable,
+			err:          true,
+		},
+		{
+			providerType: "dynatrace",
+			path:         "/api/v2/metrics",
+			statusCode:   http.StatusOK,
+		},
+		{
+			providerType: "dynatrace",
+			path:         "/api/v2/metrics",
+			statusCode:   http.StatusUnauthorized,
+			err:          true,
+		},
+		{
+			providerType: "datadog",
+			path:         "/api/v1/validate",
+			statusCode:   http.StatusOK,
+		},
+		{
+			providerType: "datadog",
+			path:         "/api/v1/validate",
+			statusCode:   http.StatusForbidden,
+			err:          true,
+		},
+		{
+			providerType: "http",
+			path:         "/",
+			statusCode:   http.StatusNotFound,
+		},
+		{
+			providerType: "http",
+			path:         "/",
+			statusCode:   http.StatusInternalServerError,
+			err:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %d", tt.providerType, tt.statusCode), func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, tt.path, r.URL.Path)
+				w.WriteHeader(tt.statusCode)
+				_, err := w.Write([]byte(tt.payload))
+				require.Nil(t, err)
+			}))
+			defer svr.Close()
+
+			p, err := NewProvider(tt.providerType, ctrl.Log.WithName("testytest"), fakeClient)
+			require.Nil(t, err)
+			healthChecker, ok := p.(KeptnSLIProviderHealthChecker)
+			require.True(t, ok)
+
+			provider := klcv1alpha2.KeptnEvaluationProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+				Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+					TargetServer: svr.URL,
+					SecretKeyRef: secretKeyRef,
+				},
+			}
+			err = healthChecker.CheckHealth(context.TODO(), provider)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestWithQueryTimeout(t *testing.T) {
+	ctx, cancel := withQueryTimeout(context.TODO())
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(DefaultQueryTimeout), deadline, time.Second)
+
+	// a deadline of the objective is kept, even if it is longer than the default
+	objectiveCtx, objectiveCancel := context.WithTimeout(context.TODO(), time.Minute)
+	defer objectiveCancel()
+	ctx, cancel = withQueryTimeout(objectiveCtx)
+	defer cancel()
+	deadline, ok = ctx.Deadline()
+	require.True(t, ok)
+	require.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+}
@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KeptnStaticProvider evaluates objectives with values read from a ConfigMap or given by the query itself,
+// e.g. to run evaluations without a metrics backend or to open and close gates manually
+type KeptnStaticProvider struct {
+	Log       logr.Logger
+	k8sClient client.Client
+}
+
+func (s *KeptnStaticProvider) EvaluateQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	value := strings.TrimSpace(objective.Query)
+	if objective.Static != nil {
+		var err error
+		value, err = s.readConfigMap(ctx, objective, provider)
+		if err != nil {
+			return "", err
+		}
+	}
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return "", fmt.Errorf("value %q is not a number", value)
+	}
+	return value, nil
+}
+
+// readConfigMap returns the value of the key of the ConfigMap referenced by the objective
+func (s *KeptnStaticProvider) readConfigMap(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	key := objective.Static.Key
+	if key == "" {
+		key = strings.TrimSpace(objective.Query)
+	}
+	s.Log.Info(fmt.Sprintf("Reading key %s of ConfigMap %s", key, objective.Static.ConfigMap))
+
+	configMap := &corev1.ConfigMap{}
+	if err := s.k8sClient.Get(ctx, types.NamespacedName{Name: objective.Static.ConfigMap, Namespace: evaluationNamespace(ctx, provider)}, configMap); err != nil {
+		return "", err
+	}
+	value, ok := configMap.Data[key]
+	if !ok {
+		return "", fmt.Errorf("ConfigMap %s contains no key %s", objective.Static.ConfigMap, key)
+	}
+	return strings.TrimSpace(value), nil
+}
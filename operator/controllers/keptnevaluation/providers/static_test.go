@@ -0,0 +1,138 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestStaticProvider(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gates",
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			"approved":    "1\n",
+			"error-rate":  "0.02",
+			"not-numeric": "yes",
+		},
+	}
+	fakeClient, err := fake.NewClient(configMap)
+	require.Nil(t, err)
+
+	tests := []struct {
+		name      string
+		objective klcv1alpha2.Objective
+		result    string
+		err       bool
+	}{
+		{
+			name:      "query",
+			objective: klcv1alpha2.Objective{Query: " 42 "},
+			result:    "42",
+		},
+		{
+			name:      "query not numeric",
+			objective: klcv1alpha2.Objective{Query: "rate(errors[5m])"},
+			err:       true,
+		},
+		{
+			name: "ConfigMap key from query",
+			objective: klcv1alpha2.Objective{
+				Query:  "approved",
+				Static: &klcv1alpha2.StaticQuery{ConfigMap: "gates"},
+			},
+			result: "1",
+		},
+		{
+			name: "ConfigMap key",
+			objective: klcv1alpha2.Objective{
+				Query:  "ignored",
+				Static: &klcv1alpha2.StaticQuery{ConfigMap: "gates", Key: "error-rate"},
+			},
+			result: "0.02",
+		},
+		{
+			name: "missing key",
+			objective: klcv1alpha2.Objective{
+				Query:  "missing",
+				Static: &klcv1alpha2.StaticQuery{ConfigMap: "gates"},
+			},
+			err: true,
+		},
+		{
+			name: "value not numeric",
+			objective: klcv1alpha2.Objective{
+				Query:  "not-numeric",
+				Static: &klcv1alpha2.StaticQuery{ConfigMap: "gates"},
+			},
+			err: true,
+		},
+		{
+			name: "missing ConfigMap",
+			objective: klcv1alpha2.Objective{
+				Query:  "approved",
+				Static: &klcv1alpha2.StaticQuery{ConfigMap: "missing"},
+			},
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ksp := KeptnStaticProvider{
+				Log:       ctrl.Log.WithName("testytest"),
+				k8sClient: fakeClient,
+			}
+			provider := klcv1alpha2.KeptnEvaluationProvider{
+				ObjectMeta: metav1.ObjectMeta{Name: "static", Namespace: "default"},
+				Spec:       klcv1alpha2.KeptnEvaluationProviderSpec{Type: "static"},
+			}
+			r, e := ksp.EvaluateQuery(context.TODO(), tt.objective, provider)
+			if tt.err {
+				require.NotNil(t, e)
+				return
+			}
+			require.Nil(t, e)
+			require.Equal(t, tt.result, r)
+		})
+	}
+}
+
+func TestStaticProvider_EvaluationNamespace(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "gates", Namespace: "team-a"},
+		Data:       map[string]string{"approved": "1"},
+	}
+	fakeClient, err := fake.NewClient(configMap)
+	require.Nil(t, err)
+
+	ksp := KeptnStaticProvider{
+		Log:       ctrl.Log.WithName("testytest"),
+		k8sClient: fakeClient,
+	}
+	// cluster providers are passed in the namespace of their secrets
+	provider := klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "static", Namespace: "keptn-lifecycle-toolkit-system"},
+		Spec:       klcv1alpha2.KeptnEvaluationProviderSpec{Type: "static"},
+	}
+	objective := klcv1alpha2.Objective{
+		Query:  "approved",
+		Static: &klcv1alpha2.StaticQuery{ConfigMap: "gates"},
+	}
+
+	_, err = ksp.EvaluateQuery(context.TODO(), objective, provider)
+	require.NotNil(t, err)
+
+	ctx := ContextWithVariables(context.TODO(), map[string]string{"namespace": "team-a"})
+	r, err := ksp.EvaluateQuery(ctx, objective, provider)
+	require.Nil(t, err)
+	require.Equal(t, "1", r)
+}
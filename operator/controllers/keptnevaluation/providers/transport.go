@@ -0,0 +1,179 @@
+package providers
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// headerRoundTripper adds authentication and custom headers to every request
+type headerRoundTripper struct {
+	next    http.RoundTripper
+	headers http.Header
+}
+
+func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the request must not be modified by a RoundTripper
+	req = req.Clone(req.Context())
+	for name, values := range h.headers {
+		req.Header[name] = values
+	}
+	return h.next.RoundTrip(req)
+}
+
+// newProviderHTTPClient returns a copy of the base client that authenticates its requests
+// and connects to the target server as configured in the evaluation provider.
+// A SecretKeyRef is sent as bearer token.
+func newProviderHTTPClient(ctx context.Context, k8sClient client.Client, base http.Client, provider klcv1alpha2.KeptnEvaluationProvider) (*http.Client, error) {
+	headers, err := getProviderHeaders(ctx, k8sClient, provider)
+	if err != nil {
+		return nil, err
+	}
+	return newHTTPClientWithHeaders(ctx, k8sClient, base, provider, headers)
+}
+
+// newProviderTransportClient returns a copy of the base client that connects to the target server as configured
+// in the evaluation provider and sends its custom headers. It is used by providers with their own authentication
+// scheme, which add the credentials to their requests themselves.
+func newProviderTransportClient(ctx context.Context, k8sClient client.Client, base http.Client, provider klcv1alpha2.KeptnEvaluationProvider) (*http.Client, error) {
+	headers := http.Header{}
+	for name, value := range provider.Spec.Headers {
+		headers.Set(name, value)
+	}
+	return newHTTPClientWithHeaders(ctx, k8sClient, base, provider, headers)
+}
+
+func newHTTPClientWithHeaders(ctx context.Context, k8sClient client.Client, base http.Client, provider klcv1alpha2.KeptnEvaluationProvider, headers http.Header) (*http.Client, error) {
+	transport := base.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if provider.Spec.TLS != nil {
+		tlsConfig, err := newTLSConfig(ctx, k8sClient, provider.Namespace, provider.Spec.TLS)
+		if err != nil {
+			return nil, err
+		}
+		httpTransport, ok := transport.(*http.Transport)
+		if !ok {
+			return nil, errors.New("the TLS configuration is not supported by the transport of the client")
+		}
+		httpTransport = httpTransport.Clone()
+		httpTransport.TLSClientConfig = tlsConfig
+		transport = httpTransport
+	}
+
+	httpClient := base
+	if len(headers) > 0 {
+		httpClient.Transport = &headerRoundTripper{next: transport, headers: headers}
+	} else {
+		httpClient.Transport = transport
+	}
+	return &httpClient, nil
+}
+
+// getProviderHeaders returns the authentication and custom headers configured in the evaluation provider
+func getProviderHeaders(ctx context.Context, k8sClient client.Client, provider klcv1alpha2.KeptnEvaluationProvider) (http.Header, error) {
+	if provider.HasSecretDefined() && provider.Spec.BasicAuth != nil {
+		return nil, errors.New("the SecretKeyRef and BasicAuth properties cannot be used together")
+	}
+
+	headers := http.Header{}
+	if provider.HasSecretDefined() {
+		token, err := getSecretValue(ctx, k8sClient, provider.Namespace, provider.Spec.SecretKeyRef)
+		if err != nil {
+			return nil, err
+		}
+		headers.Set("Authorization", "Bearer "+string(token))
+	}
+	if provider.Spec.BasicAuth != nil {
+		username, err := getSecretValue(ctx, k8sClient, provider.Namespace, provider.Spec.BasicAuth.Username)
+		if err != nil {
+			return nil, err
+		}
+		password, err := getSecretValue(ctx, k8sClient, provider.Namespace, provider.Spec.BasicAuth.Password)
+		if err != nil {
+			return nil, err
+		}
+		req := &http.Request{Header: http.Header{}}
+		req.SetBasicAuth(string(username), string(password))
+		headers.Set("Authorization", req.Header.Get("Authorization"))
+	}
+	for name, value := range provider.Spec.Headers {
+		headers.Set(name, value)
+	}
+	return headers, nil
+}
+
+func newTLSConfig(ctx context.Context, k8sClient client.Client, namespace string, config *klcv1alpha2.TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName: config.ServerName,
+		// #nosec G402 -- skipping the verification has to be configured explicitly
+		InsecureSkipVerify: config.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if config.CA != nil {
+		ca, err := getSecretValue(ctx, k8sClient, namespace, *config.CA)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("could not parse the CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (config.Cert == nil) != (config.Key == nil) {
+		return nil, errors.New("the client certificate and key have to be configured together")
+	}
+	if config.Cert != nil {
+		cert, err := getSecretValue(ctx, k8sClient, namespace, *config.Cert)
+		if err != nil {
+			return nil, err
+		}
+		key, err := getSecretValue(ctx, k8sClient, namespace, *config.Key)
+		if err != nil {
+			return nil, err
+		}
+		keyPair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{keyPair}
+	}
+	return tlsConfig, nil
+}
+
+// getSecretValue returns the value of the key of a secret in the namespace of the provider
+func getSecretValue(ctx context.Context, k8sClient client.Client, namespace string, selector corev1.SecretKeySelector) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: selector.Name, Namespace: namespace}, secret); err != nil {
+		return nil, err
+	}
+	value := secret.Data[selector.Key]
+	if len(value) == 0 {
+		return nil, fmt.Errorf("secret %s contains invalid key %s", selector.Name, selector.Key)
+	}
+	return value, nil
+}
+
+// checkHealthResponse returns an error if the health check request failed or was not successful
+func checkHealthResponse(res *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("health check failed with status code %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package providers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func secretKey(name string, key string) corev1.SecretKeySelector {
+	return corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: name},
+		Key:                  key,
+	}
+}
+
+// newClientCertificate returns a PEM encoded self-signed client certificate and its key
+func newClientCertificate(t *testing.T) ([]byte, []byte, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "keptn"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+		cert
+}
+
+func TestNewProviderHTTPClient_Headers(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prometheus-auth",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"token":    []byte("mytoken"),
+			"username": []byte("keptn"),
+			"password": []byte("secret"),
+		},
+	}
+	fakeClient, err := fake.NewClient(secret)
+	require.Nil(t, err)
+
+	tests := []struct {
+		name          string
+		spec          klcv1alpha2.KeptnEvaluationProviderSpec
+		authorization string
+		err           bool
+	}{
+		{
+			name: "bearer token",
+			spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+				SecretKeyRef: secretKey("prometheus-auth", "token"),
+				Headers:      map[string]string{"X-Scope-OrgID": "tenant-a"},
+			},
+			authorization: "Bearer mytoken",
+		},
+		{
+			name: "basic auth",
+			spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+				BasicAuth: &klcv1alpha2.BasicAuth{
+					Username: secretKey("prometheus-auth", "username"),
+					Password: secretKey("prometheus-auth", "password"),
+				},
+				Headers: map[string]string{"X-Scope-OrgID": "tenant-a"},
+			},
+			authorization: "Basic a2VwdG46c2VjcmV0",
+		},
+		{
+			name: "bearer token and basic auth",
+			spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+				SecretKeyRef: secretKey("prometheus-auth", "token"),
+				BasicAuth: &klcv1alpha2.BasicAuth{
+					Username: secretKey("prometheus-auth", "username"),
+					Password: secretKey("prometheus-auth", "password"),
+				},
+			},
+			err: true,
+		},
+		{
+			name: "missing secret",
+			spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+				SecretKeyRef: secretKey("unknown", "token"),
+			},
+			err: true,
+		},
+		{
+			name: "missing key",
+			spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+				SecretKeyRef: secretKey("prometheus-auth", "unknown"),
+			},
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, tt.authorization, r.Header.Get("Authorization"))
+				require.Equal(t, "tenant-a", r.Header.Get("X-Scope-OrgID"))
+			}))
+			defer svr.Close()
+
+			provider := klcv1alpha2.KeptnEvaluationProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+				Spec:       tt.spec,
+			}
+			httpClient, err := newProviderHTTPClient(context.TODO(), fakeClient, http.Client{}, provider)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			res, err := httpClient.Get(svr.URL)
+			require.Nil(t, err)
+			require.Nil(t, res.Body.Close())
+		})
+	}
+}
+
+func TestNewProviderHTTPClient_TLS(t *testing.T) {
+	clientCert, clientKey, parsedClientCert := newClientCertificate(t)
+
+	svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Len(t, r.TLS.PeerCertificates, 1)
+		require.Equal(t, "keptn", r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(parsedClientCert)
+	svr.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+	svr.StartTLS()
+	defer svr.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prometheus-tls",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"ca.crt":  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: svr.Certificate().Raw}),
+			"tls.crt": clientCert,
+			"tls.key": clientKey,
+			"garbage": []byte("garbage"),
+		},
+	}
+	fakeClient, err := fake.NewClient(secret)
+	require.Nil(t, err)
+
+	ca, cert, key, garbage := secretKey("prometheus-tls", "ca.crt"), secretKey("prometheus-tls", "tls.crt"), secretKey("prometheus-tls", "tls.key"), secretKey("prometheus-tls", "garbage")
+	tests := []struct {
+		name       string
+		tls        *klcv1alpha2.TLSConfig
+		requestErr bool
+		err        bool
+	}{
+		{
+			name: "mTLS",
+			tls:  &klcv1alpha2.TLSConfig{CA: &ca, Cert: &cert, Key: &key},
+		},
+		{
+			name:       "missing client certificate",
+			tls:        &klcv1alpha2.TLSConfig{CA: &ca},
+			requestErr: true,
+		},
+		{
+			name:       "unknown CA",
+			tls:        &klcv1alpha2.TLSConfig{Cert: &cert, Key: &key},
+			requestErr: true,
+		},
+		{
+			name: "insecure",
+			tls:  &klcv1alpha2.TLSConfig{Cert: &cert, Key: &key, InsecureSkipVerify: true},
+		},
+		{
+			name: "certificate without key",
+			tls:  &klcv1alpha2.TLSConfig{CA: &ca, Cert: &cert},
+			err:  true,
+		},
+		{
+			name: "invalid CA",
+			tls:  &klcv1alpha2.TLSConfig{CA: &garbage},
+			err:  true,
+		},
+		{
+			name: "invalid certificate",
+			tls:  &klcv1alpha2.TLSConfig{Cert: &garbage, Key: &key},
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := klcv1alpha2.KeptnEvaluationProvider{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+				Spec:       klcv1alpha2.KeptnEvaluationProviderSpec{TLS: tt.tls},
+			}
+			httpClient, err := newProviderHTTPClient(context.TODO(), fakeClient, http.Client{}, provider)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			res, err := httpClient.Get(svr.URL)
+			if tt.requestErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Nil(t, res.Body.Close())
+		})
+	}
+}
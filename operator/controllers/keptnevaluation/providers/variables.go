@@ -0,0 +1,46 @@
+package providers
+
+import (
+	"context"
+	"time"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+)
+
+type variablesKey struct{}
+
+type phaseStartKey struct{}
+
+// ContextWithVariables returns a context holding the variables of the lifecycle context of an evaluation,
+// which are passed on by providers delegating the evaluation to external services
+func ContextWithVariables(ctx context.Context, variables map[string]string) context.Context {
+	return context.WithValue(ctx, variablesKey{}, variables)
+}
+
+// VariablesFromContext returns the variables of the lifecycle context stored in the context
+func VariablesFromContext(ctx context.Context) map[string]string {
+	variables, _ := ctx.Value(variablesKey{}).(map[string]string)
+	return variables
+}
+
+// ContextWithPhaseStart returns a context holding the start of the evaluation,
+// which is used by providers resolving timeframes relative to it
+func ContextWithPhaseStart(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, phaseStartKey{}, start)
+}
+
+// PhaseStartFromContext returns the start of the evaluation stored in the context, or the zero time
+func PhaseStartFromContext(ctx context.Context) time.Time {
+	start, _ := ctx.Value(phaseStartKey{}).(time.Time)
+	return start
+}
+
+// evaluationNamespace returns the namespace of the evaluation stored in the variables of the context,
+// falling back to the namespace of the provider. Objects are read from the namespace of the evaluation,
+// as cluster providers are passed in the namespace their secrets are read from.
+func evaluationNamespace(ctx context.Context, provider klcv1alpha2.KeptnEvaluationProvider) string {
+	if namespace := VariablesFromContext(ctx)["namespace"]; namespace != "" {
+		return namespace
+	}
+	return provider.Namespace
+}
@@ -0,0 +1,166 @@
+package keptnevaluation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluation/providers"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// queryLimiter limits the number of concurrent queries per provider across all reconciles
+type queryLimiter struct {
+	mu     sync.Mutex
+	limits map[types.NamespacedName]chan struct{}
+}
+
+// get returns the semaphore of the provider, it is replaced if the limit of the provider changed
+func (l *queryLimiter) get(provider klcv1alpha2.KeptnEvaluationProvider) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limits == nil {
+		l.limits = make(map[types.NamespacedName]chan struct{})
+	}
+	key := types.NamespacedName{Namespace: provider.Namespace, Name: provider.Name}
+	if provider.IsClusterProvider() {
+		// cluster providers live in the namespace their secrets are read from, so they are kept apart
+		// from the namespaced providers of the same name there
+		key = types.NamespacedName{Name: klcv1alpha2.ClusterKeptnEvaluationProviderKind + "/" + provider.Name}
+	}
+	limit := provider.GetMaxConcurrentQueries()
+	semaphore, ok := l.limits[key]
+	if !ok || cap(semaphore) != limit {
+		semaphore = make(chan struct{}, limit)
+		l.limits[key] = semaphore
+	}
+	return semaphore
+}
+
+type queryResult struct {
+	done   chan struct{}
+	value  string
+	series []providers.Series
+	values []float64
+	err    error
+}
+
+// dedupProvider sends identical queries only once and limits the number of concurrent queries.
+// It is used for the objectives of a single reconcile only, so that the results are never outdated.
+type dedupProvider struct {
+	provider  providers.KeptnSLIProvider
+	semaphore chan struct{}
+	mu        sync.Mutex
+	results   map[string]*queryResult
+}
+
+func newDedupProvider(provider providers.KeptnSLIProvider, semaphore chan struct{}) *dedupProvider {
+	return &dedupProvider{
+		provider:  provider,
+		semaphore: semaphore,
+		results:   make(map[string]*queryResult),
+	}
+}
+
+func (d *dedupProvider) EvaluateQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	result := d.query(ctx, "value", objective, func(result *queryResult) {
+		result.value, result.err = d.provider.EvaluateQuery(ctx, objective, provider)
+	})
+	return result.value, result.err
+}
+
+func (d *dedupProvider) EvaluateSeriesQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) ([]providers.Series, error) {
+	multiSeriesProvider, ok := d.provider.(providers.KeptnSLIProviderMultiSeries)
+	if !ok {
+		return nil, controllererrors.ErrMultiSeriesNotSupported
+	}
+	result := d.query(ctx, "series", objective, func(result *queryResult) {
+		result.series, result.err = multiSeriesProvider.EvaluateSeriesQuery(ctx, objective, provider)
+	})
+	return result.series, result.err
+}
+
+func (d *dedupProvider) EvaluateBaselineQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) ([]float64, error) {
+	baselineProvider, ok := d.provider.(providers.KeptnSLIProviderBaseline)
+	if !ok {
+		return nil, controllererrors.ErrAnomalyDetectionNotSupported
+	}
+	result := d.query(ctx, "baseline", objective, func(result *queryResult) {
+		result.values, result.err = baselineProvider.EvaluateBaselineQuery(ctx, objective, provider)
+	})
+	return result.values, result.err
+}
+
+// query runs the query of the objective, or waits for the result if an identical query of the same kind is already running
+func (d *dedupProvider) query(ctx context.Context, kind string, objective klcv1alpha2.Objective, run func(result *queryResult)) *queryResult {
+	key, err := getQueryKey(ctx, objective)
+	if err != nil {
+		result := &queryResult{}
+		d.evaluateQuery(ctx, result, run)
+		return result
+	}
+	key = kind + ":" + key
+
+	d.mu.Lock()
+	if result, ok := d.results[key]; ok {
+		d.mu.Unlock()
+		select {
+		case <-result.done:
+			// the query may have hit the timeout of another objective, it is run again with the context of this one
+			if isContextError(result.err) && ctx.Err() == nil {
+				retry := &queryResult{}
+				d.evaluateQuery(ctx, retry, run)
+				return retry
+			}
+			return result
+		case <-ctx.Done():
+			return &queryResult{err: ctx.Err()}
+		}
+	}
+	result := &queryResult{done: make(chan struct{})}
+	d.results[key] = result
+	d.mu.Unlock()
+
+	d.evaluateQuery(ctx, result, run)
+	close(result.done)
+	return result
+}
+
+func (d *dedupProvider) evaluateQuery(ctx context.Context, result *queryResult, run func(result *queryResult)) {
+	select {
+	case d.semaphore <- struct{}{}:
+		defer func() { <-d.semaphore }()
+	case <-ctx.Done():
+		result.err = ctx.Err()
+		return
+	}
+	run(result)
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
+
+// getQueryKey identifies the query of an objective, ignoring the fields that are not sent to the provider
+func getQueryKey(ctx context.Context, objective klcv1alpha2.Objective) (string, error) {
+	query := objective.DeepCopy()
+	query.Name = ""
+	query.Source = ""
+	query.EvaluationTarget = ""
+	query.WarningTarget = ""
+	query.Weight = 0
+	query.KeySLI = false
+	query.Comparison = nil
+	query.Timeout = nil
+	key, err := json.Marshal(struct {
+		Objective *klcv1alpha2.Objective `json:"objective"`
+		Variables map[string]string      `json:"variables"`
+	}{
+		Objective: query,
+		Variables: providers.VariablesFromContext(ctx),
+	})
+	return string(key), err
+}
@@ -0,0 +1,187 @@
+package keptnevaluation
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	apicommon "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2/common"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluation/providers"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// slowSLIProvider answers every query after a delay and records the number of calls and concurrent queries
+type slowSLIProvider struct {
+	delay         time.Duration
+	calls         int32
+	running       int32
+	maxConcurrent int32
+}
+
+func (s *slowSLIProvider) EvaluateQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	atomic.AddInt32(&s.calls, 1)
+	running := atomic.AddInt32(&s.running, 1)
+	defer atomic.AddInt32(&s.running, -1)
+	for {
+		current := atomic.LoadInt32(&s.maxConcurrent)
+		if running <= current || atomic.CompareAndSwapInt32(&s.maxConcurrent, current, running) {
+			break
+		}
+	}
+	select {
+	case <-time.After(s.delay):
+		return "10", nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func TestDedupProvider_IdenticalQueries(t *testing.T) {
+	slowProvider := &slowSLIProvider{delay: 50 * time.Millisecond}
+	dedup := newDedupProvider(slowProvider, make(chan struct{}, 5))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			objective := klcv1alpha2.Objective{
+				Name:             "objective",
+				Query:            "rate(errors[5m])",
+				EvaluationTarget: "<1",
+			}
+			if i%2 == 1 {
+				objective.Name = "other-objective"
+				objective.EvaluationTarget = "<5"
+			}
+			value, err := dedup.EvaluateQuery(context.TODO(), objective, klcv1alpha2.KeptnEvaluationProvider{})
+			require.Nil(t, err)
+			require.Equal(t, "10", value)
+		}(i)
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), slowProvider.calls)
+
+	// queries with different variables are not identical
+	ctx := providers.ContextWithVariables(context.TODO(), map[string]string{"workloadVersion": "1.0.0"})
+	_, err := dedup.EvaluateQuery(ctx, klcv1alpha2.Objective{Query: "rate(errors[5m])"}, klcv1alpha2.KeptnEvaluationProvider{})
+	require.Nil(t, err)
+	require.Equal(t, int32(2), slowProvider.calls)
+}
+
+func TestDedupProvider_ConcurrencyLimit(t *testing.T) {
+	slowProvider := &slowSLIProvider{delay: 20 * time.Millisecond}
+	limiter := &queryLimiter{}
+	evaluationProvider := klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus", Namespace: "default"},
+		Spec:       klcv1alpha2.KeptnEvaluationProviderSpec{MaxConcurrentQueries: 2},
+	}
+	dedup := newDedupProvider(slowProvider, limiter.get(evaluationProvider))
+
+	var wg sync.WaitGroup
+	for _, query := range []string{"a", "b", "c", "d", "e", "f"} {
+		wg.Add(1)
+		go func(query string) {
+			defer wg.Done()
+			_, err := dedup.EvaluateQuery(context.TODO(), klcv1alpha2.Objective{Query: query}, evaluationProvider)
+			require.Nil(t, err)
+		}(query)
+	}
+	wg.Wait()
+	require.Equal(t, int32(6), slowProvider.calls)
+	require.Equal(t, int32(2), slowProvider.maxConcurrent)
+
+	// the semaphore is shared until the limit of the provider changes
+	require.Equal(t, limiter.get(evaluationProvider), limiter.get(evaluationProvider))
+	evaluationProvider.Spec.MaxConcurrentQueries = 3
+	require.Equal(t, 3, cap(limiter.get(evaluationProvider)))
+}
+
+func TestQueryLimiter_ClusterProvider(t *testing.T) {
+	limiter := &queryLimiter{}
+	namespacedProvider := klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus", Namespace: "keptn-lifecycle-toolkit-system"},
+		Spec:       klcv1alpha2.KeptnEvaluationProviderSpec{MaxConcurrentQueries: 2},
+	}
+	clusterProvider := &klcv1alpha2.ClusterKeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus"},
+		Spec: klcv1alpha2.ClusterKeptnEvaluationProviderSpec{
+			KeptnEvaluationProviderSpec: klcv1alpha2.KeptnEvaluationProviderSpec{MaxConcurrentQueries: 3},
+		},
+	}
+
+	// the cluster provider is read with the secrets of the namespace of the namespaced provider
+	namespacedSemaphore := limiter.get(namespacedProvider)
+	clusterSemaphore := limiter.get(clusterProvider.ToEvaluationProvider("keptn-lifecycle-toolkit-system"))
+	require.Equal(t, 2, cap(namespacedSemaphore))
+	require.Equal(t, 3, cap(clusterSemaphore))
+	require.Equal(t, namespacedSemaphore, limiter.get(namespacedProvider))
+	require.Equal(t, clusterSemaphore, limiter.get(clusterProvider.ToEvaluationProvider("keptn-lifecycle-toolkit-system")))
+}
+
+func TestDedupProvider_LeaderTimeout(t *testing.T) {
+	slowProvider := &slowSLIProvider{delay: 50 * time.Millisecond}
+	dedup := newDedupProvider(slowProvider, make(chan struct{}, 5))
+	objective := klcv1alpha2.Objective{Query: "rate(errors[5m])"}
+
+	leaderCtx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := dedup.EvaluateQuery(leaderCtx, objective, klcv1alpha2.KeptnEvaluationProvider{})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	}()
+	// the identical query of an objective with a longer timeout waits for the leader
+	time.Sleep(5 * time.Millisecond)
+	value, err := dedup.EvaluateQuery(context.TODO(), objective, klcv1alpha2.KeptnEvaluationProvider{})
+	wg.Wait()
+
+	// and runs the query again instead of inheriting the timeout of the leader
+	require.Nil(t, err)
+	require.Equal(t, "10", value)
+	require.Equal(t, int32(2), slowProvider.calls)
+}
+
+func TestEvaluateObjectives_Timeout(t *testing.T) {
+	r := &KeptnEvaluationReconciler{
+		Log: ctrl.Log.WithName("testytest"),
+	}
+	objectives := []klcv1alpha2.Objective{
+		{
+			Name:             "fast",
+			Query:            "fast",
+			EvaluationTarget: "<20",
+		},
+		{
+			Name:             "slow",
+			Query:            "slow",
+			EvaluationTarget: "<20",
+			Timeout:          &metav1.Duration{Duration: 10 * time.Millisecond},
+		},
+	}
+	evaluationProvider := klcv1alpha2.KeptnEvaluationProvider{
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+			QueryTimeout: metav1.Duration{Duration: time.Second},
+		},
+	}
+
+	providerCache := newProviderCache(r, &klcv1alpha2.KeptnEvaluation{})
+	providerCache.providers[""] = &resolvedProvider{
+		evaluationProvider: evaluationProvider,
+		queryProvider:      &slowSLIProvider{delay: 100 * time.Millisecond},
+	}
+
+	statusItems := r.evaluateObjectives(context.TODO(), providerCache, &klcv1alpha2.KeptnEvaluation{}, &klcv1alpha2.KeptnEvaluationDefinition{}, objectives)
+	require.Len(t, statusItems, 2)
+	require.Equal(t, apicommon.StateSucceeded, statusItems[0].Status)
+	require.Equal(t, "10", statusItems[0].Value)
+	require.Equal(t, apicommon.StateFailed, statusItems[1].Status)
+	require.Contains(t, statusItems[1].Message, context.DeadlineExceeded.Error())
+}
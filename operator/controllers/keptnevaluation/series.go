@@ -0,0 +1,185 @@
+package keptnevaluation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	apicommon "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2/common"
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluation/providers"
+)
+
+// queryValue returns the value of the objective. Results with more than one series are reduced
+// to a single value if the objective defines a reducing multiSeries mode, the series are returned as well.
+func queryValue(ctx context.Context, provider providers.KeptnSLIProvider, objective klcv1alpha2.Objective, evaluationProvider klcv1alpha2.KeptnEvaluationProvider) (string, []providers.Series, error) {
+	if objective.MultiSeries == "" {
+		value, err := provider.EvaluateQuery(ctx, objective, evaluationProvider)
+		return value, nil, err
+	}
+	series, err := querySeries(ctx, provider, objective, evaluationProvider)
+	if err != nil {
+		return "", nil, err
+	}
+	value, err := reduceSeries(series, objective.MultiSeries)
+	return value, series, err
+}
+
+func querySeries(ctx context.Context, provider providers.KeptnSLIProvider, objective klcv1alpha2.Objective, evaluationProvider klcv1alpha2.KeptnEvaluationProvider) ([]providers.Series, error) {
+	multiSeriesProvider, ok := provider.(providers.KeptnSLIProviderMultiSeries)
+	if !ok {
+		return nil, controllererrors.ErrMultiSeriesNotSupported
+	}
+	return multiSeriesProvider.EvaluateSeriesQuery(ctx, objective, evaluationProvider)
+}
+
+// reduceSeries reduces the values of the series to a single value with the sum, average, maximum or minimum
+func reduceSeries(series []providers.Series, mode klcv1alpha2.MultiSeriesMode) (string, error) {
+	if len(series) == 0 {
+		return "", controllererrors.ErrNoValues
+	}
+	var result float64
+	for i, s := range series {
+		value, err := strconv.ParseFloat(s.Value, 64)
+		if err != nil {
+			return "", fmt.Errorf("could not parse query result %q: %w", s.Value, err)
+		}
+		switch {
+		case i == 0:
+			result = value
+		case mode == klcv1alpha2.MultiSeriesSum || mode == klcv1alpha2.MultiSeriesAvg:
+			result += value
+		case mode == klcv1alpha2.MultiSeriesMax:
+			result = math.Max(result, value)
+		case mode == klcv1alpha2.MultiSeriesMin:
+			result = math.Min(result, value)
+		default:
+			return "", fmt.Errorf("multiSeries mode %s does not reduce the series to a single value", mode)
+		}
+	}
+	if mode == klcv1alpha2.MultiSeriesAvg {
+		result /= float64(len(series))
+	}
+	return strconv.FormatFloat(result, 'f', -1, 64), nil
+}
+
+// checkSeries checks the value of a single series against the targets of the objective
+func checkSeries(objective klcv1alpha2.Objective, value string, scoringEnabled bool) (apicommon.KeptnState, error) {
+	item := &klcv1alpha2.EvaluationStatusItem{Value: value}
+	check, err := checkValue(objective, item)
+	if err != nil {
+		return apicommon.StateFailed, err
+	}
+	if check {
+		return apicommon.StateSucceeded, nil
+	}
+	if scoringEnabled && objective.WarningTarget != "" {
+		warning, err := checkTarget(objective.WarningTarget, item)
+		if err != nil {
+			return apicommon.StateFailed, err
+		}
+		if warning {
+			return apicommon.StateWarning, nil
+		}
+	}
+	return apicommon.StateFailed, nil
+}
+
+// getSeriesStatus checks every series against the targets of the objective
+func getSeriesStatus(objective klcv1alpha2.Objective, series []providers.Series, scoringEnabled bool) ([]klcv1alpha2.SeriesStatus, error) {
+	seriesStatus := make([]klcv1alpha2.SeriesStatus, 0, len(series))
+	for _, s := range series {
+		state, err := checkSeries(objective, s.Value, scoringEnabled)
+		if err != nil {
+			return nil, err
+		}
+		seriesStatus = append(seriesStatus, klcv1alpha2.SeriesStatus{
+			Labels: s.Labels,
+			Value:  s.Value,
+			Status: state,
+		})
+	}
+	return seriesStatus, nil
+}
+
+// getReducedSeriesStatus records the series of a result that has been reduced to a single value.
+// They are only checked against the targets if the targets apply to the value itself.
+func getReducedSeriesStatus(objective klcv1alpha2.Objective, series []providers.Series, scoringEnabled bool) []klcv1alpha2.SeriesStatus {
+	if objective.Comparison == nil {
+		if seriesStatus, err := getSeriesStatus(objective, series, scoringEnabled); err == nil {
+			return seriesStatus
+		}
+	}
+	seriesStatus := make([]klcv1alpha2.SeriesStatus, 0, len(series))
+	for _, s := range series {
+		seriesStatus = append(seriesStatus, klcv1alpha2.SeriesStatus{
+			Labels: s.Labels,
+			Value:  s.Value,
+		})
+	}
+	return seriesStatus
+}
+
+// combineSeriesStatus returns the status of an objective that requires all or any of its series to pass
+func combineSeriesStatus(mode klcv1alpha2.MultiSeriesMode, seriesStatus []klcv1alpha2.SeriesStatus) apicommon.KeptnState {
+	succeeded, warning := 0, 0
+	for _, s := range seriesStatus {
+		switch s.Status {
+		case apicommon.StateSucceeded:
+			succeeded++
+		case apicommon.StateWarning:
+			warning++
+		}
+	}
+	if mode == klcv1alpha2.MultiSeriesAll {
+		switch {
+		case succeeded == len(seriesStatus):
+			return apicommon.StateSucceeded
+		case succeeded+warning == len(seriesStatus):
+			return apicommon.StateWarning
+		}
+		return apicommon.StateFailed
+	}
+	switch {
+	case succeeded > 0:
+		return apicommon.StateSucceeded
+	case warning > 0:
+		return apicommon.StateWarning
+	}
+	return apicommon.StateFailed
+}
+
+// evaluatePerSeriesObjective checks every series of the query result against the targets,
+// the objective passes if all or any of the series pass depending on its multiSeries mode
+func evaluatePerSeriesObjective(ctx context.Context, provider providers.KeptnSLIProvider, objective klcv1alpha2.Objective, evaluationProvider klcv1alpha2.KeptnEvaluationProvider, scoringEnabled bool) *klcv1alpha2.EvaluationStatusItem {
+	statusItem := &klcv1alpha2.EvaluationStatusItem{
+		Status: apicommon.StateFailed,
+	}
+	if objective.Comparison != nil {
+		statusItem.Message = fmt.Sprintf("comparisons are not supported with multiSeries %s", objective.MultiSeries)
+		return statusItem
+	}
+	series, err := querySeries(ctx, provider, objective, evaluationProvider)
+	if err != nil {
+		statusItem.Message = err.Error()
+		return statusItem
+	}
+	seriesStatus, err := getSeriesStatus(objective, series, scoringEnabled)
+	if err != nil {
+		statusItem.Message = err.Error()
+		return statusItem
+	}
+
+	passed := 0
+	for _, s := range seriesStatus {
+		if s.Status.IsSucceeded() {
+			passed++
+		}
+	}
+	statusItem.Series = seriesStatus
+	statusItem.Status = combineSeriesStatus(objective.MultiSeries, seriesStatus)
+	statusItem.Message = fmt.Sprintf("%d of %d series passed", passed, len(seriesStatus))
+	return statusItem
+}
@@ -0,0 +1,135 @@
+package keptnevaluation
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	apicommon "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2/common"
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluation/providers"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSeriesProvider returns the same series for every query
+type fakeSeriesProvider struct {
+	series []providers.Series
+}
+
+func (f *fakeSeriesProvider) EvaluateQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) (string, error) {
+	return f.series[0].Value, nil
+}
+
+func (f *fakeSeriesProvider) EvaluateSeriesQuery(ctx context.Context, objective klcv1alpha2.Objective, provider klcv1alpha2.KeptnEvaluationProvider) ([]providers.Series, error) {
+	return f.series, nil
+}
+
+var testSeries = []providers.Series{
+	{Labels: map[string]string{"pod": "a"}, Value: "1"},
+	{Labels: map[string]string{"pod": "b"}, Value: "4"},
+	{Labels: map[string]string{"pod": "c"}, Value: "7"},
+}
+
+func TestReduceSeries(t *testing.T) {
+	tests := []struct {
+		mode   klcv1alpha2.MultiSeriesMode
+		series []providers.Series
+		result string
+		err    bool
+	}{
+		{mode: klcv1alpha2.MultiSeriesSum, series: testSeries, result: "12"},
+		{mode: klcv1alpha2.MultiSeriesAvg, series: testSeries, result: "4"},
+		{mode: klcv1alpha2.MultiSeriesMax, series: testSeries, result: "7"},
+		{mode: klcv1alpha2.MultiSeriesMin, series: testSeries, result: "1"},
+		{mode: klcv1alpha2.MultiSeriesAll, series: testSeries, err: true},
+		{mode: klcv1alpha2.MultiSeriesSum, series: nil, err: true},
+		{mode: klcv1alpha2.MultiSeriesSum, series: []providers.Series{{Value: "1"}, {Value: "NaN?"}}, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			result, err := reduceSeries(tt.series, tt.mode)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.result, result)
+		})
+	}
+}
+
+func TestCombineSeriesStatus(t *testing.T) {
+	status := func(states ...apicommon.KeptnState) []klcv1alpha2.SeriesStatus {
+		seriesStatus := []klcv1alpha2.SeriesStatus{}
+		for _, state := range states {
+			seriesStatus = append(seriesStatus, klcv1alpha2.SeriesStatus{Status: state})
+		}
+		return seriesStatus
+	}
+
+	tests := []struct {
+		name   string
+		mode   klcv1alpha2.MultiSeriesMode
+		status []klcv1alpha2.SeriesStatus
+		want   apicommon.KeptnState
+	}{
+		{name: "all passed", mode: klcv1alpha2.MultiSeriesAll, status: status(apicommon.StateSucceeded, apicommon.StateSucceeded), want: apicommon.StateSucceeded},
+		{name: "all with warning", mode: klcv1alpha2.MultiSeriesAll, status: status(apicommon.StateSucceeded, apicommon.StateWarning), want: apicommon.StateWarning},
+		{name: "all with failure", mode: klcv1alpha2.MultiSeriesAll, status: status(apicommon.StateSucceeded, apicommon.StateFailed), want: apicommon.StateFailed},
+		{name: "any passed", mode: klcv1alpha2.MultiSeriesAny, status: status(apicommon.StateFailed, apicommon.StateSucceeded), want: apicommon.StateSucceeded},
+		{name: "any with warning", mode: klcv1alpha2.MultiSeriesAny, status: status(apicommon.StateFailed, apicommon.StateWarning), want: apicommon.StateWarning},
+		{name: "any failed", mode: klcv1alpha2.MultiSeriesAny, status: status(apicommon.StateFailed, apicommon.StateFailed), want: apicommon.StateFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, combineSeriesStatus(tt.mode, tt.status))
+		})
+	}
+}
+
+func TestEvaluatePerSeriesObjective(t *testing.T) {
+	provider := &fakeSeriesProvider{series: testSeries}
+	objective := klcv1alpha2.Objective{
+		Name:             "error-rate",
+		Query:            "rate(errors[5m])",
+		EvaluationTarget: "<5",
+		MultiSeries:      klcv1alpha2.MultiSeriesAll,
+	}
+
+	statusItem := evaluatePerSeriesObjective(context.TODO(), provider, objective, klcv1alpha2.KeptnEvaluationProvider{}, false)
+	require.Equal(t, apicommon.StateFailed, statusItem.Status)
+	require.Equal(t, "2 of 3 series passed", statusItem.Message)
+	require.Equal(t, []klcv1alpha2.SeriesStatus{
+		{Labels: map[string]string{"pod": "a"}, Value: "1", Status: apicommon.StateSucceeded},
+		{Labels: map[string]string{"pod": "b"}, Value: "4", Status: apicommon.StateSucceeded},
+		{Labels: map[string]string{"pod": "c"}, Value: "7", Status: apicommon.StateFailed},
+	}, statusItem.Series)
+
+	objective.MultiSeries = klcv1alpha2.MultiSeriesAny
+	statusItem = evaluatePerSeriesObjective(context.TODO(), provider, objective, klcv1alpha2.KeptnEvaluationProvider{}, false)
+	require.Equal(t, apicommon.StateSucceeded, statusItem.Status)
+
+	// providers without support for multiple series fail the objective
+	statusItem = evaluatePerSeriesObjective(context.TODO(), &slowSLIProvider{}, objective, klcv1alpha2.KeptnEvaluationProvider{}, false)
+	require.Equal(t, apicommon.StateFailed, statusItem.Status)
+	require.Equal(t, controllererrors.ErrMultiSeriesNotSupported.Error(), statusItem.Message)
+}
+
+func TestQueryValue_Reduced(t *testing.T) {
+	dedup := newDedupProvider(&fakeSeriesProvider{series: testSeries}, make(chan struct{}, 1))
+	objective := klcv1alpha2.Objective{
+		Query:       "rate(errors[5m])",
+		MultiSeries: klcv1alpha2.MultiSeriesMax,
+	}
+	value, series, err := queryValue(context.TODO(), dedup, objective, klcv1alpha2.KeptnEvaluationProvider{})
+	require.Nil(t, err)
+	require.Equal(t, "7", value)
+	require.Equal(t, testSeries, series)
+
+	// without a multiSeries mode only the single value is queried
+	objective.MultiSeries = ""
+	value, series, err = queryValue(context.TODO(), dedup, objective, klcv1alpha2.KeptnEvaluationProvider{})
+	require.Nil(t, err)
+	require.Equal(t, "1", value)
+	require.Nil(t, series)
+}
@@ -0,0 +1,320 @@
+package keptnevaluation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
+)
+
+// Target is a parsed Objective.EvaluationTarget expression.
+//
+// The grammar supports comparisons (">10", ">=10", "<10", "<=10", "==10", "!=10"),
+// inclusive ranges ("between 100 and 200") and compound expressions combined with
+// "and"/"&&" and "or"/"||", where "and" binds tighter than "or" and parentheses can be
+// used for grouping, e.g. "(>=100 and <200) or ==0"
+type Target interface {
+	// Evaluate returns true if the given value satisfies the target
+	Evaluate(value float64) bool
+	String() string
+}
+
+type comparisonTarget struct {
+	operator string
+	value    float64
+}
+
+func (c comparisonTarget) Evaluate(value float64) bool {
+	switch c.operator {
+	case ">":
+		return value > c.value
+	case ">=":
+		return value >= c.value
+	case "<":
+		return value < c.value
+	case "<=":
+		return value <= c.value
+	case "==":
+		return value == c.value
+	case "!=":
+		return value != c.value
+	default:
+		return false
+	}
+}
+
+func (c comparisonTarget) String() string {
+	return c.operator + formatTargetNumber(c.value)
+}
+
+type rangeTarget struct {
+	low  float64
+	high float64
+}
+
+func (r rangeTarget) Evaluate(value float64) bool {
+	return value >= r.low && value <= r.high
+}
+
+func (r rangeTarget) String() string {
+	return "between " + formatTargetNumber(r.low) + " and " + formatTargetNumber(r.high)
+}
+
+type logicalTarget struct {
+	operator string
+	left     Target
+	right    Target
+}
+
+func (l logicalTarget) Evaluate(value float64) bool {
+	if l.operator == "and" {
+		return l.left.Evaluate(value) && l.right.Evaluate(value)
+	}
+	return l.left.Evaluate(value) || l.right.Evaluate(value)
+}
+
+func (l logicalTarget) String() string {
+	return "(" + l.left.String() + " " + l.operator + " " + l.right.String() + ")"
+}
+
+func formatTargetNumber(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// ParseTarget parses an evaluation target expression, returning an error describing
+// the position of the first invalid token if the expression is malformed
+func ParseTarget(expression string) (Target, error) {
+	p := &targetParser{expression: expression}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	if len(p.tokens) == 0 {
+		return nil, p.errorf(0, "expression is empty")
+	}
+	target, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		t := p.peek()
+		return nil, p.errorf(t.pos, "unexpected %q", t.text)
+	}
+	return target, nil
+}
+
+type targetTokenKind int
+
+const (
+	tokenOperator targetTokenKind = iota
+	tokenNumber
+	tokenWord
+	tokenOpenParen
+	tokenCloseParen
+)
+
+type targetToken struct {
+	kind targetTokenKind
+	text string
+	pos  int
+}
+
+type targetParser struct {
+	expression string
+	tokens     []targetToken
+	current    int
+}
+
+func (p *targetParser) errorf(pos int, format string, args ...interface{}) error {
+	return fmt.Errorf("%w %q: %s at position %d", controllererrors.ErrInvalidEvaluationTarget, p.expression, fmt.Sprintf(format, args...), pos)
+}
+
+func (p *targetParser) tokenize() error {
+	s := p.expression
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			p.tokens = append(p.tokens, targetToken{kind: tokenOpenParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			p.tokens = append(p.tokens, targetToken{kind: tokenCloseParen, text: ")", pos: i})
+			i++
+		case strings.HasPrefix(s[i:], "&&"):
+			p.tokens = append(p.tokens, targetToken{kind: tokenWord, text: "and", pos: i})
+			i += 2
+		case strings.HasPrefix(s[i:], "||"):
+			p.tokens = append(p.tokens, targetToken{kind: tokenWord, text: "or", pos: i})
+			i += 2
+		case strings.ContainsRune("<>=!", c):
+			op := s[i : i+1]
+			if i+1 < len(s) && s[i+1] == '=' {
+				op = s[i : i+2]
+			}
+			if op == "=" || op == "!" {
+				return p.errorf(i, "invalid operator %q", op)
+			}
+			p.tokens = append(p.tokens, targetToken{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+		case c == '-' || c == '+' || c == '.' || unicode.IsDigit(c):
+			end := scanTargetNumber(s, i)
+			p.tokens = append(p.tokens, targetToken{kind: tokenNumber, text: s[i:end], pos: i})
+			i = end
+		case unicode.IsLetter(c):
+			end := i
+			for end < len(s) && unicode.IsLetter(rune(s[end])) {
+				end++
+			}
+			p.tokens = append(p.tokens, targetToken{kind: tokenWord, text: strings.ToLower(s[i:end]), pos: i})
+			i = end
+		default:
+			return p.errorf(i, "unexpected character %q", c)
+		}
+	}
+	return nil
+}
+
+func scanTargetNumber(s string, start int) int {
+	i := start
+	if s[i] == '-' || s[i] == '+' {
+		i++
+	}
+	for i < len(s) && (unicode.IsDigit(rune(s[i])) || s[i] == '.') {
+		i++
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '-' || s[j] == '+') {
+			j++
+		}
+		if j < len(s) && unicode.IsDigit(rune(s[j])) {
+			i = j
+			for i < len(s) && unicode.IsDigit(rune(s[i])) {
+				i++
+			}
+		}
+	}
+	return i
+}
+
+func (p *targetParser) done() bool {
+	return p.current >= len(p.tokens)
+}
+
+func (p *targetParser) peek() targetToken {
+	return p.tokens[p.current]
+}
+
+func (p *targetParser) endPos() int {
+	return len(p.expression)
+}
+
+func (p *targetParser) acceptWord(word string) bool {
+	if !p.done() && p.peek().kind == tokenWord && p.peek().text == word {
+		p.current++
+		return true
+	}
+	return false
+}
+
+func (p *targetParser) parseOr() (Target, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptWord("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalTarget{operator: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *targetParser) parseAnd() (Target, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptWord("and") {
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalTarget{operator: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *targetParser) parseTerm() (Target, error) {
+	if p.done() {
+		return nil, p.errorf(p.endPos(), "expected a comparison")
+	}
+	t := p.peek()
+	switch {
+	case t.kind == tokenOpenParen:
+		p.current++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.peek().kind != tokenCloseParen {
+			return nil, p.errorf(p.nextPos(), "expected \")\"")
+		}
+		p.current++
+		return inner, nil
+	case t.kind == tokenOperator:
+		p.current++
+		value, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		return comparisonTarget{operator: t.text, value: value}, nil
+	case t.kind == tokenWord && t.text == "between":
+		p.current++
+		low, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptWord("and") {
+			return nil, p.errorf(p.nextPos(), "expected \"and\" in range")
+		}
+		high, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		if low > high {
+			return nil, p.errorf(t.pos, "lower bound %s is greater than upper bound %s", formatTargetNumber(low), formatTargetNumber(high))
+		}
+		return rangeTarget{low: low, high: high}, nil
+	default:
+		return nil, p.errorf(t.pos, "expected a comparison operator or \"between\", got %q", t.text)
+	}
+}
+
+func (p *targetParser) nextPos() int {
+	if p.done() {
+		return p.endPos()
+	}
+	return p.peek().pos
+}
+
+func (p *targetParser) parseNumber() (float64, error) {
+	if p.done() {
+		return 0, p.errorf(p.endPos(), "expected a number")
+	}
+	t := p.peek()
+	if t.kind != tokenNumber {
+		return 0, p.errorf(t.pos, "expected a number, got %q", t.text)
+	}
+	value, err := strconv.ParseFloat(t.text, 64)
+	if err != nil {
+		return 0, p.errorf(t.pos, "invalid number %q", t.text)
+	}
+	p.current++
+	return value, nil
+}
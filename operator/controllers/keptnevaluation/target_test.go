@@ -0,0 +1,176 @@
+package keptnevaluation
+
+import (
+	"errors"
+	"testing"
+
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		normalized string
+		pass       []float64
+		fail       []float64
+	}{
+		{
+			name:       "greater than",
+			expression: ">10",
+			normalized: ">10",
+			pass:       []float64{10.5, 11},
+			fail:       []float64{10, 9},
+		},
+		{
+			name:       "greater or equal with spaces",
+			expression: " >= 10 ",
+			normalized: ">=10",
+			pass:       []float64{10, 11},
+			fail:       []float64{9.99},
+		},
+		{
+			name:       "less or equal negative",
+			expression: "<=-1.5",
+			normalized: "<=-1.5",
+			pass:       []float64{-1.5, -3},
+			fail:       []float64{0},
+		},
+		{
+			name:       "equal",
+			expression: "==0",
+			normalized: "==0",
+			pass:       []float64{0},
+			fail:       []float64{1},
+		},
+		{
+			name:       "not equal scientific notation",
+			expression: "!=1e3",
+			normalized: "!=1000",
+			pass:       []float64{999},
+			fail:       []float64{1000},
+		},
+		{
+			name:       "range",
+			expression: "between 100 and 200",
+			normalized: "between 100 and 200",
+			pass:       []float64{100, 150, 200},
+			fail:       []float64{99, 201},
+		},
+		{
+			name:       "and binds tighter than or",
+			expression: "==0 or >=100 and <200",
+			normalized: "(==0 or (>=100 and <200))",
+			pass:       []float64{0, 100},
+			fail:       []float64{50, 200},
+		},
+		{
+			name:       "parentheses and symbolic operators",
+			expression: "(<5 || >10) && !=20",
+			normalized: "((<5 or >10) and !=20)",
+			pass:       []float64{1, 11},
+			fail:       []float64{7, 20},
+		},
+		{
+			name:       "range combined with comparison",
+			expression: "BETWEEN 1 AND 5 or >100",
+			normalized: "(between 1 and 5 or >100)",
+			pass:       []float64{3, 101},
+			fail:       []float64{50},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := ParseTarget(tt.expression)
+			require.Nil(t, err)
+			require.Equal(t, tt.normalized, target.String())
+			for _, v := range tt.pass {
+				require.True(t, target.Evaluate(v), "expected %v to pass", v)
+			}
+			for _, v := range tt.fail {
+				require.False(t, target.Evaluate(v), "expected %v to fail", v)
+			}
+		})
+	}
+}
+
+func TestParseTarget_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		message    string
+	}{
+		{
+			name:       "empty",
+			expression: "  ",
+			message:    "expression is empty at position 0",
+		},
+		{
+			name:       "missing operator",
+			expression: "10",
+			message:    "expected a comparison operator or \"between\", got \"10\" at position 0",
+		},
+		{
+			name:       "reversed operator",
+			expression: "=>5",
+			message:    "invalid operator \"=\" at position 0",
+		},
+		{
+			name:       "missing number",
+			expression: ">",
+			message:    "expected a number at position 1",
+		},
+		{
+			name:       "not a number",
+			expression: ">abc",
+			message:    "expected a number, got \"abc\" at position 1",
+		},
+		{
+			name:       "nan",
+			expression: "nan",
+			message:    "expected a comparison operator or \"between\", got \"nan\" at position 0",
+		},
+		{
+			name:       "incomplete range",
+			expression: "between 1 5",
+			message:    "expected \"and\" in range at position 10",
+		},
+		{
+			name:       "inverted range",
+			expression: "between 5 and 1",
+			message:    "lower bound 5 is greater than upper bound 1 at position 0",
+		},
+		{
+			name:       "dangling conjunction",
+			expression: ">5 and",
+			message:    "expected a comparison at position 6",
+		},
+		{
+			name:       "unbalanced parentheses",
+			expression: "(>5 or <1",
+			message:    "expected \")\" at position 9",
+		},
+		{
+			name:       "trailing token",
+			expression: ">5 <1",
+			message:    "unexpected \"<\" at position 3",
+		},
+		{
+			name:       "unknown character",
+			expression: ">5 % 2",
+			message:    "unexpected character '%' at position 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := ParseTarget(tt.expression)
+			require.Nil(t, target)
+			require.NotNil(t, err)
+			require.True(t, errors.Is(err, controllererrors.ErrInvalidEvaluationTarget))
+			require.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package keptnevaluation
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+)
+
+// QueryContext holds the lifecycle context variables that can be used as Go template
+// placeholders in objective queries, e.g. {{.Workload}} or {{.PhaseStartTime.Unix}}
+type QueryContext struct {
+	Workload        string
+	WorkloadVersion string
+	AppName         string
+	AppVersion      string
+	Namespace       string
+	PreviousVersion string
+	PhaseStartTime  time.Time
+}
+
+func newQueryContext(evaluation *klcv1alpha2.KeptnEvaluation) QueryContext {
+	return QueryContext{
+		Workload:        evaluation.Spec.Workload,
+		WorkloadVersion: evaluation.Spec.WorkloadVersion,
+		AppName:         evaluation.Spec.AppName,
+		AppVersion:      evaluation.Spec.AppVersion,
+		Namespace:       evaluation.Namespace,
+		PreviousVersion: evaluation.Spec.PreviousVersion,
+		PhaseStartTime:  evaluation.Status.StartTime.Time,
+	}
+}
+
+// forPreviousVersion returns the context of the previous version of the workload or app
+func (c QueryContext) forPreviousVersion() QueryContext {
+	if c.Workload != "" {
+		c.WorkloadVersion = c.PreviousVersion
+	} else {
+		c.AppVersion = c.PreviousVersion
+	}
+	return c
+}
+
+// variables returns the values of the context, keyed by their lower camel case names
+func (c QueryContext) variables() map[string]string {
+	variables := map[string]string{
+		"workload":        c.Workload,
+		"workloadVersion": c.WorkloadVersion,
+		"appName":         c.AppName,
+		"appVersion":      c.AppVersion,
+		"namespace":       c.Namespace,
+		"previousVersion": c.PreviousVersion,
+	}
+	if !c.PhaseStartTime.IsZero() {
+		variables["phaseStartTime"] = c.PhaseStartTime.UTC().Format(time.RFC3339)
+	}
+	return variables
+}
+
+func isTemplatedQuery(query string) bool {
+	return strings.Contains(query, "{{")
+}
+
+// renderQuery replaces the placeholders of the query with the values of the context
+func renderQuery(query string, queryContext QueryContext) (string, error) {
+	if !isTemplatedQuery(query) {
+		return query, nil
+	}
+	tmpl, err := template.New("query").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", fmt.Errorf("could not parse query template: %w", err)
+	}
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, queryContext); err != nil {
+		return "", fmt.Errorf("could not render query template: %w", err)
+	}
+	return rendered.String(), nil
+}
+
+// resolveQueryRange replaces a time window starting at the start of the phase with its current length.
+// The window starts at the given observation start, which is the start of the evaluation after its initial delay.
+func resolveQueryRange(queryRange *klcv1alpha2.QueryRange, observationStart time.Time) *klcv1alpha2.QueryRange {
+	if queryRange == nil || !queryRange.SincePhaseStart || observationStart.IsZero() {
+		return queryRange
+	}
+	resolved := queryRange.DeepCopy()
+	// rounded, so that identical queries of the same reconcile can be deduplicated
+	resolved.Interval.Duration = time.Since(observationStart).Round(time.Second)
+	if resolved.Interval.Duration < time.Second {
+		resolved.Interval.Duration = time.Second
+	}
+	return resolved
+}
@@ -0,0 +1,125 @@
+package keptnevaluation
+
+import (
+	"testing"
+	"time"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRenderQuery(t *testing.T) {
+	startTime := time.Date(2022, 12, 1, 10, 0, 0, 0, time.UTC)
+	evaluation := &klcv1alpha2.KeptnEvaluation{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "my-namespace",
+		},
+		Spec: klcv1alpha2.KeptnEvaluationSpec{
+			AppName:         "my-app",
+			AppVersion:      "1.0.0",
+			Workload:        "my-app-my-workload",
+			WorkloadVersion: "2.0.0",
+			PreviousVersion: "1.9.0",
+		},
+		Status: klcv1alpha2.KeptnEvaluationStatus{
+			StartTime: metav1.NewTime(startTime),
+		},
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		context QueryContext
+		result  string
+		err     bool
+	}{
+		{
+			name:    "plain query",
+			query:   "sum(up)",
+			context: newQueryContext(evaluation),
+			result:  "sum(up)",
+		},
+		{
+			name:    "all variables",
+			query:   `{{.Workload}} {{.WorkloadVersion}} {{.AppName}} {{.AppVersion}} {{.Namespace}} {{.PreviousVersion}} {{.PhaseStartTime.Unix}}`,
+			context: newQueryContext(evaluation),
+			result:  "my-app-my-workload 2.0.0 my-app 1.0.0 my-namespace 1.9.0 1669888800",
+		},
+		{
+			name:    "previous workload version",
+			query:   `up{version="{{.WorkloadVersion}}"}`,
+			context: newQueryContext(evaluation).forPreviousVersion(),
+			result:  `up{version="1.9.0"}`,
+		},
+		{
+			name:  "previous app version",
+			query: `up{version="{{.AppVersion}}"}`,
+			context: QueryContext{
+				AppVersion:      "1.0.0",
+				PreviousVersion: "0.9.0",
+			}.forPreviousVersion(),
+			result: `up{version="0.9.0"}`,
+		},
+		{
+			name:    "unknown variable",
+			query:   `up{version="{{.Unknown}}"}`,
+			context: newQueryContext(evaluation),
+			err:     true,
+		},
+		{
+			name:    "invalid template",
+			query:   `up{version="{{.WorkloadVersion"}`,
+			context: newQueryContext(evaluation),
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := renderQuery(tt.query, tt.context)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.result, result)
+		})
+	}
+}
+
+func TestResolveQueryRange(t *testing.T) {
+	require.Nil(t, resolveQueryRange(nil, time.Time{}))
+
+	queryRange := &klcv1alpha2.QueryRange{
+		Interval:        metav1.Duration{Duration: 5 * time.Minute},
+		SincePhaseStart: true,
+	}
+	// the evaluation has not started yet
+	require.Equal(t, queryRange, resolveQueryRange(queryRange, time.Time{}))
+
+	resolved := resolveQueryRange(queryRange, time.Now().Add(-10*time.Minute))
+	require.InDelta(t, (10 * time.Minute).Seconds(), resolved.Interval.Duration.Seconds(), 5)
+	require.Equal(t, 5*time.Minute, queryRange.Interval.Duration)
+}
+
+func TestQueryContextVariables(t *testing.T) {
+	queryContext := QueryContext{
+		Workload:        "my-workload",
+		WorkloadVersion: "2.0.0",
+		Namespace:       "my-namespace",
+		PreviousVersion: "1.0.0",
+	}
+	require.Equal(t, map[string]string{
+		"workload":        "my-workload",
+		"workloadVersion": "2.0.0",
+		"appName":         "",
+		"appVersion":      "",
+		"namespace":       "my-namespace",
+		"previousVersion": "1.0.0",
+	}, queryContext.variables())
+
+	queryContext.PhaseStartTime = time.Date(2022, 12, 1, 10, 0, 0, 0, time.UTC)
+	require.Equal(t, "2022-12-01T10:00:00Z", queryContext.variables()["phaseStartTime"])
+	require.Equal(t, "1.0.0", queryContext.forPreviousVersion().variables()["workloadVersion"])
+}
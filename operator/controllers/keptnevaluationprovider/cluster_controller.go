@@ -0,0 +1,73 @@
+package keptnevaluationprovider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ClusterKeptnEvaluationProviderReconciler reconciles a ClusterKeptnEvaluationProvider object
+type ClusterKeptnEvaluationProviderReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Log      logr.Logger
+	// HealthCheckInterval is the time between two health checks of a provider
+	HealthCheckInterval time.Duration
+	// SecretNamespace is the namespace the secrets of ClusterKeptnEvaluationProviders are read from
+	SecretNamespace string
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=clusterkeptnevaluationproviders,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=clusterkeptnevaluationproviders/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get
+
+// Reconcile checks the health of the target server of a ClusterKeptnEvaluationProvider with the credentials
+// read from the SecretNamespace and reports the result in its status. The check is repeated after the HealthCheckInterval.
+func (r *ClusterKeptnEvaluationProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Log.Info("Reconciling ClusterKeptnEvaluationProvider")
+
+	clusterProvider := &klcv1alpha2.ClusterKeptnEvaluationProvider{}
+	if err := r.Client.Get(ctx, req.NamespacedName, clusterProvider); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "Failed to get the ClusterKeptnEvaluationProvider")
+		return ctrl.Result{}, err
+	}
+
+	provider := clusterProvider.ToEvaluationProvider(r.SecretNamespace)
+	wasUnhealthy := provider.IsUnhealthy()
+	checkHealth(ctx, r.Log, r.Client, &provider)
+
+	clusterProvider.Status = provider.Status
+	if err := r.Client.Status().Update(ctx, clusterProvider); err != nil {
+		r.Log.Error(err, "could not update status")
+		return ctrl.Result{}, err
+	}
+
+	if provider.IsUnhealthy() && !wasUnhealthy {
+		r.Recorder.Event(clusterProvider, "Warning", "ProviderUnhealthy", fmt.Sprintf("health check failed: %s / Name: %s", provider.Status.LastError, provider.Name))
+	} else if !provider.IsUnhealthy() && wasUnhealthy {
+		r.Recorder.Event(clusterProvider, "Normal", "ProviderHealthy", fmt.Sprintf("health check succeeded / Name: %s", provider.Name))
+	}
+
+	return ctrl.Result{RequeueAfter: r.HealthCheckInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterKeptnEvaluationProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&klcv1alpha2.ClusterKeptnEvaluationProvider{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,74 @@
+package keptnevaluationprovider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestClusterKeptnEvaluationProviderReconciler_Reconcile(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer cluster-token" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer svr.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "http-token", Namespace: "keptn-lifecycle-toolkit-system"},
+		Data:       map[string][]byte{"token": []byte("cluster-token")},
+	}
+	provider := &klcv1alpha2.ClusterKeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "http"},
+		Spec: klcv1alpha2.ClusterKeptnEvaluationProviderSpec{
+			KeptnEvaluationProviderSpec: klcv1alpha2.KeptnEvaluationProviderSpec{
+				Type:         "http",
+				TargetServer: svr.URL,
+				SecretKeyRef: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "http-token"},
+					Key:                  "token",
+				},
+			},
+		},
+	}
+	fakeClient, err := fake.NewClient(secret, provider)
+	require.Nil(t, err)
+
+	r := &ClusterKeptnEvaluationProviderReconciler{
+		Client:              fakeClient,
+		Recorder:            record.NewFakeRecorder(100),
+		Log:                 ctrl.Log.WithName("testytest"),
+		HealthCheckInterval: time.Minute,
+		SecretNamespace:     "keptn-lifecycle-toolkit-system",
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "http"}}
+
+	result, err := r.Reconcile(context.TODO(), req)
+	require.Nil(t, err)
+	require.Equal(t, time.Minute, result.RequeueAfter)
+
+	updated := &klcv1alpha2.ClusterKeptnEvaluationProvider{}
+	require.Nil(t, fakeClient.Get(context.TODO(), req.NamespacedName, updated))
+	require.True(t, updated.Status.Reachable)
+	require.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, klcv1alpha2.ProviderReadyCondition))
+
+	// the secrets are never read from another namespace
+	r.SecretNamespace = "default"
+	_, err = r.Reconcile(context.TODO(), req)
+	require.Nil(t, err)
+	require.Nil(t, fakeClient.Get(context.TODO(), req.NamespacedName, updated))
+	require.False(t, updated.Status.Reachable)
+	require.Contains(t, <-r.Recorder.(*record.FakeRecorder).Events, "ProviderUnhealthy")
+}
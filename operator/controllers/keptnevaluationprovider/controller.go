@@ -0,0 +1,139 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keptnevaluationprovider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluation/providers"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// KeptnEvaluationProviderReconciler reconciles a KeptnEvaluationProvider object
+type KeptnEvaluationProviderReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Log      logr.Logger
+	// HealthCheckInterval is the time between two health checks of a provider
+	HealthCheckInterval time.Duration
+}
+
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationproviders,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lifecycle.keptn.sh,resources=keptnevaluationproviders/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get
+
+// Reconcile checks the health of the target server of a KeptnEvaluationProvider with the configured credentials
+// and reports the result in its status. The check is repeated after the HealthCheckInterval.
+func (r *KeptnEvaluationProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Log.Info("Reconciling KeptnEvaluationProvider")
+
+	provider := &klcv1alpha2.KeptnEvaluationProvider{}
+	if err := r.Client.Get(ctx, req.NamespacedName, provider); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "Failed to get the KeptnEvaluationProvider")
+		return ctrl.Result{}, err
+	}
+
+	wasUnhealthy := provider.IsUnhealthy()
+	checkHealth(ctx, r.Log, r.Client, provider)
+
+	if err := r.Client.Status().Update(ctx, provider); err != nil {
+		r.Log.Error(err, "could not update status")
+		return ctrl.Result{}, err
+	}
+
+	if provider.IsUnhealthy() && !wasUnhealthy {
+		r.Recorder.Event(provider, "Warning", "ProviderUnhealthy", fmt.Sprintf("health check failed: %s / Namespace: %s, Name: %s", provider.Status.LastError, provider.Namespace, provider.Name))
+	} else if !provider.IsUnhealthy() && wasUnhealthy {
+		r.Recorder.Event(provider, "Normal", "ProviderHealthy", fmt.Sprintf("health check succeeded / Namespace: %s, Name: %s", provider.Namespace, provider.Name))
+	}
+
+	return ctrl.Result{RequeueAfter: r.HealthCheckInterval}, nil
+}
+
+// checkHealth runs the health check of the provider and updates its status accordingly
+func checkHealth(ctx context.Context, log logr.Logger, k8sClient client.Client, provider *klcv1alpha2.KeptnEvaluationProvider) {
+	condition := metav1.Condition{
+		Type:               klcv1alpha2.ProviderReadyCondition,
+		ObservedGeneration: provider.Generation,
+	}
+
+	sliProvider, err := providers.NewProvider(provider.GetType(), log, k8sClient)
+	if err != nil {
+		provider.Status.Reachable = false
+		provider.Status.LastError = err.Error()
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = klcv1alpha2.ProviderUnreachableReason
+		condition.Message = err.Error()
+		meta.SetStatusCondition(&provider.Status.Conditions, condition)
+		return
+	}
+
+	healthChecker, ok := sliProvider.(providers.KeptnSLIProviderHealthChecker)
+	if !ok {
+		// the health is not known, but the provider is not regarded as unhealthy by the evaluations
+		provider.Status.Reachable = false
+		provider.Status.LastError = ""
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = klcv1alpha2.ProviderHealthCheckNotSupportedReason
+		condition.Message = fmt.Sprintf("providers of type %s do not support health checks", provider.GetType())
+		meta.SetStatusCondition(&provider.Status.Conditions, condition)
+		return
+	}
+
+	start := time.Now()
+	err = healthChecker.CheckHealth(ctx, *provider)
+	provider.Status.LastCheckTime = metav1.NewTime(start)
+	provider.Status.Latency = metav1.Duration{Duration: time.Since(start).Round(time.Millisecond)}
+	if err != nil {
+		log.Info("Health check of provider failed", "provider", provider.Name, "error", err.Error())
+		provider.Status.Reachable = false
+		provider.Status.LastError = err.Error()
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = klcv1alpha2.ProviderUnreachableReason
+		condition.Message = err.Error()
+	} else {
+		provider.Status.Reachable = true
+		provider.Status.LastError = ""
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = klcv1alpha2.ProviderReachableReason
+		condition.Message = "health check succeeded"
+	}
+	meta.SetStatusCondition(&provider.Status.Conditions, condition)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KeptnEvaluationProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&klcv1alpha2.KeptnEvaluationProvider{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
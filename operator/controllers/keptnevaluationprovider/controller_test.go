@@ -0,0 +1,154 @@
+package keptnevaluationprovider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const promHealthyPayload = "{\"status\":\"success\",\"data\":{\"resultType\":\"vector\",\"result\":[{\"metric\":{},\"value\":[1669714193.275,\"1\"]}]}}"
+
+func TestKeptnEvaluationProviderReconciler_Reconcile(t *testing.T) {
+	healthy := true
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, err := w.Write([]byte(promHealthyPayload))
+		require.Nil(t, err)
+	}))
+	defer svr.Close()
+
+	provider := &klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prometheus-prod",
+			Namespace: "default",
+		},
+		Spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+			Type:         "prometheus",
+			TargetServer: svr.URL,
+		},
+	}
+	fakeClient, err := fake.NewClient(provider)
+	require.Nil(t, err)
+
+	recorder := record.NewFakeRecorder(100)
+	r := &KeptnEvaluationProviderReconciler{
+		Client:              fakeClient,
+		Recorder:            recorder,
+		Log:                 ctrl.Log.WithName("testytest"),
+		HealthCheckInterval: time.Minute,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "prometheus-prod"}}
+
+	reconcileAndGet := func() *klcv1alpha2.KeptnEvaluationProvider {
+		result, err := r.Reconcile(context.TODO(), req)
+		require.Nil(t, err)
+		require.Equal(t, time.Minute, result.RequeueAfter)
+		updated := &klcv1alpha2.KeptnEvaluationProvider{}
+		require.Nil(t, fakeClient.Get(context.TODO(), req.NamespacedName, updated))
+		return updated
+	}
+
+	updated := reconcileAndGet()
+	require.True(t, updated.Status.Reachable)
+	require.Empty(t, updated.Status.LastError)
+	require.False(t, updated.IsUnhealthy())
+	require.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, klcv1alpha2.ProviderReadyCondition))
+	require.Len(t, recorder.Events, 0)
+
+	healthy = false
+	updated = reconcileAndGet()
+	require.False(t, updated.Status.Reachable)
+	require.NotEmpty(t, updated.Status.LastError)
+	require.True(t, updated.IsUnhealthy())
+	require.Contains(t, <-recorder.Events, "ProviderUnhealthy")
+
+	// no event is emitted as long as the health does not change
+	updated = reconcileAndGet()
+	require.True(t, updated.IsUnhealthy())
+	require.Len(t, recorder.Events, 0)
+
+	healthy = true
+	updated = reconcileAndGet()
+	require.False(t, updated.IsUnhealthy())
+	require.Contains(t, <-recorder.Events, "ProviderHealthy")
+}
+
+func TestKeptnEvaluationProviderReconciler_Reconcile_Types(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      klcv1alpha2.KeptnEvaluationProviderSpec
+		unhealthy bool
+		ready     metav1.ConditionStatus
+		reason    string
+	}{
+		{
+			name:   "health check not supported",
+			spec:   klcv1alpha2.KeptnEvaluationProviderSpec{Type: "kubernetes"},
+			ready:  metav1.ConditionUnknown,
+			reason: klcv1alpha2.ProviderHealthCheckNotSupportedReason,
+		},
+		{
+			name:      "unsupported type",
+			spec:      klcv1alpha2.KeptnEvaluationProviderSpec{Type: "unknown"},
+			unhealthy: true,
+			ready:     metav1.ConditionFalse,
+			reason:    klcv1alpha2.ProviderUnreachableReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &klcv1alpha2.KeptnEvaluationProvider{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-provider",
+					Namespace: "default",
+				},
+				Spec: tt.spec,
+			}
+			fakeClient, err := fake.NewClient(provider)
+			require.Nil(t, err)
+			r := &KeptnEvaluationProviderReconciler{
+				Client:   fakeClient,
+				Recorder: record.NewFakeRecorder(100),
+				Log:      ctrl.Log.WithName("testytest"),
+			}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "my-provider"}}
+			_, err = r.Reconcile(context.TODO(), req)
+			require.Nil(t, err)
+
+			updated := &klcv1alpha2.KeptnEvaluationProvider{}
+			require.Nil(t, fakeClient.Get(context.TODO(), req.NamespacedName, updated))
+			require.Equal(t, tt.unhealthy, updated.IsUnhealthy())
+			condition := meta.FindStatusCondition(updated.Status.Conditions, klcv1alpha2.ProviderReadyCondition)
+			require.Equal(t, tt.reason, condition.Reason)
+			require.Equal(t, tt.ready, condition.Status)
+			require.False(t, updated.Status.Reachable)
+		})
+	}
+}
+
+func TestKeptnEvaluationProviderReconciler_Reconcile_NotFound(t *testing.T) {
+	fakeClient, err := fake.NewClient()
+	require.Nil(t, err)
+	r := &KeptnEvaluationProviderReconciler{
+		Client: fakeClient,
+		Log:    ctrl.Log.WithName("testytest"),
+	}
+	result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "unknown"}})
+	require.Nil(t, err)
+	require.Equal(t, ctrl.Result{}, result)
+}
@@ -45,5 +45,5 @@ func (r *KeptnWorkloadInstanceReconciler) reconcilePrePostEvaluation(ctx context
 	if err != nil {
 		return apicommon.StateUnknown, err
 	}
-	return overallState, nil
+	return apicommon.GetOverallStateWithWarnings(state), nil
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnapp"
 	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnappversion"
 	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluation"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluationprovider"
 	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptntask"
 	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptntaskdefinition"
 	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnworkload"
@@ -81,7 +82,10 @@ func init() {
 }
 
 type envConfig struct {
-	OTelCollectorURL string `envconfig:"OTEL_COLLECTOR_URL" default:""`
+	OTelCollectorURL            string        `envconfig:"OTEL_COLLECTOR_URL" default:""`
+	ProviderHealthCheckInterval time.Duration `envconfig:"PROVIDER_HEALTH_CHECK_INTERVAL" default:"1m"`
+	// ClusterProviderSecretNamespace is the only namespace the secrets of ClusterKeptnEvaluationProviders are read from
+	ClusterProviderSecretNamespace string `envconfig:"CLUSTER_PROVIDER_SECRET_NAMESPACE" default:"keptn-lifecycle-toolkit-system"`
 }
 
 func main() {
@@ -337,6 +341,8 @@ func main() {
 		Recorder: mgr.GetEventRecorderFor("keptnevaluation-controller"),
 		Tracer:   otel.Tracer("keptn/operator/evaluation"),
 		Meters:   meters,
+
+		ClusterProviderSecretNamespace: env.ClusterProviderSecretNamespace,
 	}
 
 	if err = (evaluationReconciler).SetupWithManager(mgr); err != nil {
@@ -344,10 +350,46 @@ func main() {
 		os.Exit(1)
 	}
 
+	evaluationProviderReconciler := &keptnevaluationprovider.KeptnEvaluationProviderReconciler{
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+		Log:                 ctrl.Log.WithName("KeptnEvaluationProvider Controller"),
+		Recorder:            mgr.GetEventRecorderFor("keptnevaluationprovider-controller"),
+		HealthCheckInterval: env.ProviderHealthCheckInterval,
+	}
+	if err = (evaluationProviderReconciler).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KeptnEvaluationProvider")
+		os.Exit(1)
+	}
+
+	clusterEvaluationProviderReconciler := &keptnevaluationprovider.ClusterKeptnEvaluationProviderReconciler{
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+		Log:                 ctrl.Log.WithName("ClusterKeptnEvaluationProvider Controller"),
+		Recorder:            mgr.GetEventRecorderFor("clusterkeptnevaluationprovider-controller"),
+		HealthCheckInterval: env.ProviderHealthCheckInterval,
+		SecretNamespace:     env.ClusterProviderSecretNamespace,
+	}
+	if err = (clusterEvaluationProviderReconciler).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterKeptnEvaluationProvider")
+		os.Exit(1)
+	}
+
 	if err = (&lifecyclev1alpha2.KeptnApp{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "KeptnApp")
 		os.Exit(1)
 	}
+	if !disableWebhook {
+		mgr.GetWebhookServer().Register("/validate-lifecycle-keptn-sh-v1alpha2-keptnevaluationdefinition", &webhook.Admission{
+			Handler: &webhooks.EvaluationDefinitionValidatingWebhook{
+				Client: mgr.GetClient(),
+				Log:    ctrl.Log.WithName("KeptnEvaluationDefinition Validating Webhook"),
+			}})
+		mgr.GetWebhookServer().Register("/validate-lifecycle-keptn-sh-v1alpha2-keptnevaluationprovider", &webhook.Admission{
+			Handler: &webhooks.EvaluationProviderValidatingWebhook{
+				Log: ctrl.Log.WithName("KeptnEvaluationProvider Validating Webhook"),
+			}})
+	}
 	//+kubebuilder:scaffold:builder
 
 	err = meter.RegisterCallback(
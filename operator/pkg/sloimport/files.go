@@ -0,0 +1,122 @@
+package sloimport
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AddPath adds the SLI/SLO files found at the path, directories are walked recursively.
+// Keptn v1 services are found by their slo.yaml, with their sli.yaml either next to it or in a
+// directory named after the provider type, e.g. prometheus/sli.yaml. The definition of a service
+// is named after its directory. Other YAML files are added if they contain OpenSLO documents.
+func (i *Importer) AddPath(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	root := path
+	if !info.IsDir() {
+		root = filepath.Dir(path)
+	}
+
+	return filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !isYAMLFile(file) {
+			return nil
+		}
+		switch strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)) {
+		case "slo":
+			return i.addKeptnV1Service(root, file)
+		case "sli":
+			// SLI files are added with the SLO file of their service
+			return nil
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if !IsOpenSLO(data) {
+			return nil
+		}
+		return i.AddOpenSLO(file, data)
+	})
+}
+
+func (i *Importer) addKeptnV1Service(root string, sloPath string) error {
+	dir := filepath.Dir(sloPath)
+	sliPaths, err := findKeptnV1SLIFiles(dir)
+	if err != nil {
+		return err
+	}
+	if len(sliPaths) == 0 {
+		i.addIssue(sloPath, "the SLO file is skipped, there is no sli.yaml next to it or in a provider directory")
+		return nil
+	}
+	sliPath := sliPaths[0]
+	if len(sliPaths) > 1 {
+		i.addIssue(sloPath, "the SLO file has several SLI files, only %s is imported", sliPath)
+	}
+	providerType := ""
+	if filepath.Dir(sliPath) != dir {
+		providerType = filepath.Base(filepath.Dir(sliPath))
+	}
+
+	sli, err := os.ReadFile(sliPath)
+	if err != nil {
+		return err
+	}
+	slo, err := os.ReadFile(sloPath)
+	if err != nil {
+		return err
+	}
+	return i.AddKeptnV1(KeptnV1Files{
+		Name:         getKeptnV1ServiceName(root, dir),
+		ProviderType: providerType,
+		SLIPath:      sliPath,
+		SLI:          sli,
+		SLOPath:      sloPath,
+		SLO:          slo,
+	})
+}
+
+// findKeptnV1SLIFiles returns the SLI files of the service in the directory, the one next to
+// the SLO file comes first, followed by the ones in provider directories
+func findKeptnV1SLIFiles(dir string) ([]string, error) {
+	files := []string{}
+	for _, pattern := range []string{"sli.y*ml", "*/sli.y*ml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			if isYAMLFile(match) {
+				files = append(files, match)
+			}
+		}
+	}
+	return files, nil
+}
+
+// getKeptnV1ServiceName returns the path of the directory relative to the imported path, e.g. production-carts
+func getKeptnV1ServiceName(root string, dir string) string {
+	relative, err := filepath.Rel(root, dir)
+	if err != nil || relative == "." {
+		absolute, err := filepath.Abs(dir)
+		if err != nil {
+			return filepath.Base(dir)
+		}
+		return filepath.Base(absolute)
+	}
+	return strings.ReplaceAll(relative, string(filepath.Separator), "-")
+}
+
+func isYAMLFile(file string) bool {
+	extension := filepath.Ext(file)
+	return extension == ".yaml" || extension == ".yml"
+}
@@ -0,0 +1,149 @@
+package sloimport
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, path string, content string) {
+	require.Nil(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestImporter_AddPath(t *testing.T) {
+	dir := t.TempDir()
+	slo := "objectives:\n  - sli: throughput\n    pass:\n      - criteria:\n          - \">100\"\n"
+	sli := "indicators:\n  throughput: sum(rate(http_requests_total[5m]))\n"
+	// SLI file in a provider directory
+	writeTestFile(t, filepath.Join(dir, "production", "carts", "slo.yaml"), slo)
+	writeTestFile(t, filepath.Join(dir, "production", "carts", "dynatrace", "sli.yaml"), sli)
+	// SLI file next to the SLO file
+	writeTestFile(t, filepath.Join(dir, "production", "orders", "slo.yml"), slo)
+	writeTestFile(t, filepath.Join(dir, "production", "orders", "sli.yml"), sli)
+	// no SLI file
+	writeTestFile(t, filepath.Join(dir, "staging", "carts", "slo.yaml"), slo)
+	writeTestFile(t, filepath.Join(dir, "openslo", "latency.yaml"), `
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: latency
+spec:
+  indicator:
+    metadata:
+      name: latency
+    spec:
+      thresholdMetric:
+        metricSource:
+          type: Prometheus
+          spec:
+            query: latency
+  objectives:
+    - op: lt
+      value: 250
+`)
+	writeTestFile(t, filepath.Join(dir, "kustomization.yaml"), "resources: []\n")
+
+	importer := NewImporter(Options{ProviderType: "prometheus", TargetServer: "http://metrics:9090"})
+	require.Nil(t, importer.AddPath(dir))
+	result := importer.Result()
+
+	names := []string{}
+	for _, definition := range result.Definitions {
+		names = append(names, definition.Name+"/"+definition.Spec.Source)
+	}
+	require.ElementsMatch(t, []string{"production-carts/dynatrace", "production-orders/prometheus", "latency/prometheus"}, names)
+	require.Len(t, result.Providers, 2)
+	require.Len(t, result.Issues, 1)
+	require.Equal(t, filepath.Join(dir, "staging", "carts", "slo.yaml"), result.Issues[0].Source)
+
+	// a single SLO file is named after its directory
+	importer = NewImporter(Options{ProviderType: "prometheus"})
+	require.Nil(t, importer.AddPath(filepath.Join(dir, "production", "orders", "slo.yml")))
+	result = importer.Result()
+	require.Len(t, result.Definitions, 1)
+	require.Equal(t, "orders", result.Definitions[0].Name)
+
+	require.NotNil(t, importer.AddPath(filepath.Join(dir, "missing")))
+}
+
+func TestResult_Manifests(t *testing.T) {
+	importer := NewImporter(Options{Namespace: "podtato", ProviderType: "prometheus", TargetServer: "http://prometheus:9090"})
+	err := importer.AddKeptnV1(KeptnV1Files{
+		Name:    "Carts_Service",
+		SLIPath: "sli.yaml",
+		SLI:     []byte("indicators:\n  throughput: sum(rate(http_requests_total[5m]))\n"),
+		SLOPath: "slo.yaml",
+		SLO:     []byte("objectives:\n  - sli: throughput\n    pass:\n      - criteria:\n          - \">100\"\n"),
+	})
+	require.Nil(t, err)
+
+	manifests, err := importer.Result().Manifests()
+	require.Nil(t, err)
+	require.Equal(t, `---
+apiVersion: lifecycle.keptn.sh/v1alpha2
+kind: KeptnEvaluationProvider
+metadata:
+  name: prometheus
+  namespace: podtato
+spec:
+  targetServer: http://prometheus:9090
+---
+apiVersion: lifecycle.keptn.sh/v1alpha2
+kind: KeptnEvaluationDefinition
+metadata:
+  name: carts-service
+  namespace: podtato
+spec:
+  objectives:
+  - evaluationTarget: '>100'
+    name: throughput
+    query: sum(rate(http_requests_total[5m]))
+  source: prometheus
+`, string(manifests))
+}
+
+func TestResult_Manifests_UnknownTargetServer(t *testing.T) {
+	importer := NewImporter(Options{ProviderType: "prometheus"})
+	err := importer.AddKeptnV1(KeptnV1Files{
+		Name:    "carts",
+		SLIPath: "sli.yaml",
+		SLI:     []byte("indicators:\n  throughput: sum(rate(http_requests_total[5m]))\n"),
+		SLOPath: "slo.yaml",
+		SLO:     []byte("objectives:\n  - sli: throughput\n    pass:\n      - criteria:\n          - \">100\"\n"),
+	})
+	require.Nil(t, err)
+	result := importer.Result()
+	require.Len(t, result.Issues, 1)
+	require.Contains(t, result.Issues[0].Message, TargetServerPlaceholder)
+
+	// the placeholder keeps the required target server, so that the manifests can be applied
+	manifests, err := result.Manifests()
+	require.Nil(t, err)
+	require.Equal(t, `---
+apiVersion: lifecycle.keptn.sh/v1alpha2
+kind: KeptnEvaluationProvider
+metadata:
+  name: prometheus
+spec:
+  targetServer: <target-server>
+---
+apiVersion: lifecycle.keptn.sh/v1alpha2
+kind: KeptnEvaluationDefinition
+metadata:
+  name: carts
+spec:
+  objectives:
+  - evaluationTarget: '>100'
+    name: throughput
+    query: sum(rate(http_requests_total[5m]))
+  source: prometheus
+`, string(manifests))
+
+	// empty required fields are kept as well, only optional ones are pruned
+	fields := map[string]interface{}{"query": "", "warningTarget": "", "initialDelay": "0s"}
+	pruneZeroValues(fields)
+	require.Equal(t, map[string]interface{}{"query": ""}, fields)
+}
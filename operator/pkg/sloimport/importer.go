@@ -0,0 +1,210 @@
+// Package sloimport converts Keptn v1 SLI/SLO files and OpenSLO documents into
+// KeptnEvaluationDefinition and KeptnEvaluationProvider manifests
+package sloimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"sigs.k8s.io/yaml"
+)
+
+// Options are applied to every imported manifest
+type Options struct {
+	// Namespace of the manifests, they are not namespaced if it is empty
+	Namespace string
+	// ProviderType is the type of the provider querying Keptn v1 SLIs, e.g. prometheus or dynatrace
+	ProviderType string
+	// TargetServer of providers whose address is not part of the imported files
+	TargetServer string
+}
+
+// TargetServerPlaceholder is the target server of providers whose address is neither part of the imported files
+// nor set in the options. The manifests can be applied, but the placeholder has to be replaced before the providers work.
+const TargetServerPlaceholder = "<target-server>"
+
+// Issue describes a construct that could not be translated, or was translated with a different meaning
+type Issue struct {
+	// Source is the file or document the construct was found in
+	Source  string
+	Message string
+}
+
+func (i Issue) String() string {
+	return i.Source + ": " + i.Message
+}
+
+// Result holds the imported manifests and the issues found while translating them
+type Result struct {
+	Providers   []klcv1alpha2.KeptnEvaluationProvider
+	Definitions []klcv1alpha2.KeptnEvaluationDefinition
+	Issues      []Issue
+}
+
+// Importer collects SLI/SLO files and converts them into evaluation definitions and providers.
+// OpenSLO documents can reference SLIs and data sources of other files, so they are converted
+// once all files have been added.
+type Importer struct {
+	options Options
+	result  Result
+	openSLO openSLODocuments
+}
+
+func NewImporter(options Options) *Importer {
+	return &Importer{
+		options: options,
+		openSLO: newOpenSLODocuments(),
+	}
+}
+
+// Result converts the collected OpenSLO documents and returns every imported manifest
+func (i *Importer) Result() Result {
+	i.importOpenSLO()
+	i.openSLO = newOpenSLODocuments()
+	return i.result
+}
+
+func (i *Importer) addIssue(source string, format string, args ...interface{}) {
+	i.result.Issues = append(i.result.Issues, Issue{Source: source, Message: fmt.Sprintf(format, args...)})
+}
+
+// addProvider adds the provider unless an identical one was already imported
+func (i *Importer) addProvider(source string, provider klcv1alpha2.KeptnEvaluationProvider) {
+	for _, existing := range i.result.Providers {
+		if existing.Name != provider.Name {
+			continue
+		}
+		if !reflect.DeepEqual(existing.Spec, provider.Spec) {
+			i.addIssue(source, "KeptnEvaluationProvider %s was already imported with another configuration, the first one is kept", provider.Name)
+		}
+		return
+	}
+	i.result.Providers = append(i.result.Providers, provider)
+}
+
+func (i *Importer) addDefinition(source string, definition klcv1alpha2.KeptnEvaluationDefinition) {
+	for _, existing := range i.result.Definitions {
+		if existing.Name == definition.Name {
+			i.addIssue(source, "KeptnEvaluationDefinition %s was already imported, the duplicate is skipped", definition.Name)
+			return
+		}
+	}
+	i.result.Definitions = append(i.result.Definitions, definition)
+}
+
+// newProvider returns a provider of the given type. Providers named after their type do not need to set it.
+// An unknown target server is reported and set to the TargetServerPlaceholder.
+func (i *Importer) newProvider(source string, name string, providerType string, targetServer string) klcv1alpha2.KeptnEvaluationProvider {
+	if targetServer == "" {
+		i.addIssue(source, "the target server of KeptnEvaluationProvider %s is not known, replace the placeholder %s", name, TargetServerPlaceholder)
+		targetServer = TargetServerPlaceholder
+	}
+	provider := klcv1alpha2.KeptnEvaluationProvider{}
+	provider.APIVersion = klcv1alpha2.GroupVersion.String()
+	provider.Kind = "KeptnEvaluationProvider"
+	provider.Name = name
+	provider.Namespace = i.options.Namespace
+	provider.Spec.TargetServer = targetServer
+	if name != providerType {
+		provider.Spec.Type = providerType
+	}
+	return provider
+}
+
+func (i *Importer) newDefinition(name string, source string) klcv1alpha2.KeptnEvaluationDefinition {
+	definition := klcv1alpha2.KeptnEvaluationDefinition{}
+	definition.APIVersion = klcv1alpha2.GroupVersion.String()
+	definition.Kind = "KeptnEvaluationDefinition"
+	definition.Name = name
+	definition.Namespace = i.options.Namespace
+	definition.Spec.Source = source
+	return definition
+}
+
+// Manifests returns the providers and definitions of the result as multi-document YAML
+func (r Result) Manifests() ([]byte, error) {
+	objects := make([]interface{}, 0, len(r.Providers)+len(r.Definitions))
+	for i := range r.Providers {
+		objects = append(objects, &r.Providers[i])
+	}
+	for i := range r.Definitions {
+		objects = append(objects, &r.Definitions[i])
+	}
+
+	var out bytes.Buffer
+	for _, object := range objects {
+		manifest, err := toManifest(object)
+		if err != nil {
+			return nil, err
+		}
+		out.WriteString("---\n")
+		out.Write(manifest)
+	}
+	return out.Bytes(), nil
+}
+
+// toManifest marshals the object to YAML without its status and the fields set by the API server
+func toManifest(object interface{}) ([]byte, error) {
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "status")
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+	pruneZeroValues(fields)
+	return yaml.Marshal(fields)
+}
+
+// requiredFields are kept by pruneZeroValues even if they are empty, the CRDs reject objects without them
+var requiredFields = map[string]bool{
+	"targetServer":     true,
+	"name":             true,
+	"query":            true,
+	"evaluationTarget": true,
+}
+
+// pruneZeroValues removes the empty strings, zero durations and empty objects of optional fields without omitempty,
+// so that the defaults of the CRDs apply
+func pruneZeroValues(fields map[string]interface{}) {
+	for key, value := range fields {
+		switch v := value.(type) {
+		case string:
+			if (v == "" || v == "0s") && !requiredFields[key] {
+				delete(fields, key)
+			}
+		case map[string]interface{}:
+			pruneZeroValues(v)
+			if len(v) == 0 {
+				delete(fields, key)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if object, ok := item.(map[string]interface{}); ok {
+					pruneZeroValues(object)
+				}
+			}
+		}
+	}
+}
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// sanitizeName turns the given name into a valid name of a Kubernetes object
+func sanitizeName(name string) string {
+	name = invalidNameCharacters.ReplaceAllString(strings.ToLower(name), "-")
+	if len(name) > 253 {
+		name = name[:253]
+	}
+	return strings.Trim(name, "-.")
+}
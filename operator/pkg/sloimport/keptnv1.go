@@ -0,0 +1,379 @@
+package sloimport
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"sigs.k8s.io/yaml"
+)
+
+// KeptnV1Files are the SLI and SLO files of a Keptn v1 service
+type KeptnV1Files struct {
+	// Name of the imported KeptnEvaluationDefinition
+	Name string
+	// ProviderType of the SLI provider, defaults to the provider type of the options
+	ProviderType string
+	SLIPath      string
+	SLI          []byte
+	SLOPath      string
+	SLO          []byte
+}
+
+type keptnV1SLI struct {
+	Indicators map[string]string `json:"indicators"`
+}
+
+type keptnV1SLO struct {
+	Comparison *keptnV1Comparison `json:"comparison,omitempty"`
+	Filter     map[string]string  `json:"filter,omitempty"`
+	Objectives []keptnV1Objective `json:"objectives"`
+	TotalScore *keptnV1TotalScore `json:"total_score,omitempty"`
+}
+
+type keptnV1Comparison struct {
+	AggregateFunction         string `json:"aggregate_function,omitempty"`
+	CompareWith               string `json:"compare_with,omitempty"`
+	IncludeResultWithScore    string `json:"include_result_with_score,omitempty"`
+	NumberOfComparisonResults int    `json:"number_of_comparison_results,omitempty"`
+}
+
+type keptnV1Objective struct {
+	SLI         string            `json:"sli"`
+	DisplayName string            `json:"displayName,omitempty"`
+	Pass        []keptnV1Criteria `json:"pass,omitempty"`
+	Warning     []keptnV1Criteria `json:"warning,omitempty"`
+	Weight      int               `json:"weight,omitempty"`
+	KeySLI      bool              `json:"key_sli,omitempty"`
+}
+
+type keptnV1Criteria struct {
+	Criteria []string `json:"criteria"`
+}
+
+type keptnV1TotalScore struct {
+	Pass    string `json:"pass"`
+	Warning string `json:"warning,omitempty"`
+}
+
+// AddKeptnV1 converts the SLO file of a Keptn v1 service into a KeptnEvaluationDefinition
+// querying the indicators of its SLI file, and adds the provider of the SLI file
+func (i *Importer) AddKeptnV1(files KeptnV1Files) error {
+	sli := keptnV1SLI{}
+	if err := yaml.Unmarshal(files.SLI, &sli); err != nil {
+		return fmt.Errorf("could not parse %s: %w", files.SLIPath, err)
+	}
+	slo := keptnV1SLO{}
+	if err := yaml.Unmarshal(files.SLO, &slo); err != nil {
+		return fmt.Errorf("could not parse %s: %w", files.SLOPath, err)
+	}
+	providerType := strings.ToLower(files.ProviderType)
+	if providerType == "" {
+		providerType = strings.ToLower(i.options.ProviderType)
+	}
+	if providerType == "" {
+		return fmt.Errorf("the provider type of %s is not known", files.SLIPath)
+	}
+
+	source := files.SLOPath
+	definition := i.newDefinition(sanitizeName(files.Name), providerType)
+	relative := false
+	for _, objective := range slo.Objectives {
+		converted, ok := i.convertKeptnV1Objective(source, sli.Indicators, objective, providerType)
+		if !ok {
+			continue
+		}
+		relative = relative || converted.Comparison != nil
+		definition.Spec.Objectives = append(definition.Spec.Objectives, converted)
+	}
+	if len(definition.Spec.Objectives) == 0 {
+		i.addIssue(source, "no objective could be imported, KeptnEvaluationDefinition %s is skipped", definition.Name)
+		return nil
+	}
+
+	if slo.TotalScore != nil {
+		totalScore, err := convertKeptnV1TotalScore(*slo.TotalScore)
+		if err != nil {
+			i.addIssue(source, "total_score is not imported: %s", err.Error())
+		} else {
+			definition.Spec.TotalScore = totalScore
+		}
+	}
+	if definition.Spec.TotalScore == nil {
+		for _, objective := range definition.Spec.Objectives {
+			if objective.WarningTarget != "" {
+				i.addIssue(source, "warning criteria are only checked with a total_score, every objective has to pass")
+				break
+			}
+		}
+	}
+	if relative {
+		i.checkKeptnV1Comparison(source, slo.Comparison)
+	}
+	if len(slo.Filter) > 0 {
+		keys := make([]string, 0, len(slo.Filter))
+		for key := range slo.Filter {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		i.addIssue(source, "filter %s is not imported, use the variables of the evaluation in the queries instead", strings.Join(keys, ", "))
+	}
+
+	i.addProvider(files.SLIPath, i.newProvider(files.SLIPath, providerType, providerType, i.options.TargetServer))
+	i.addDefinition(source, definition)
+	return nil
+}
+
+func (i *Importer) convertKeptnV1Objective(source string, indicators map[string]string, objective keptnV1Objective, providerType string) (klcv1alpha2.Objective, bool) {
+	query, ok := indicators[objective.SLI]
+	if !ok {
+		i.addIssue(source, "objective %s is skipped, the SLI is not defined", objective.SLI)
+		return klcv1alpha2.Objective{}, false
+	}
+	if len(objective.Pass) == 0 {
+		i.addIssue(source, "objective %s is skipped, informational SLIs without pass criteria are not supported", objective.SLI)
+		return klcv1alpha2.Objective{}, false
+	}
+
+	converted := klcv1alpha2.Objective{
+		Name:   objective.SLI,
+		Weight: objective.Weight,
+		KeySLI: objective.KeySLI,
+	}
+	if !i.convertKeptnV1Query(source, objective.SLI, query, providerType, &converted) {
+		return klcv1alpha2.Objective{}, false
+	}
+
+	pass := i.parseKeptnV1Criteria(source, objective.SLI, objective.Pass)
+	warning := i.parseKeptnV1Criteria(source, objective.SLI, objective.Warning)
+	kind := selectCriterionKind(pass)
+	converted.EvaluationTarget = i.formatKeptnV1Criteria(source, objective.SLI, pass, kind)
+	if converted.EvaluationTarget == "" {
+		i.addIssue(source, "objective %s is skipped, none of its pass criteria could be imported", objective.SLI)
+		return klcv1alpha2.Objective{}, false
+	}
+	converted.WarningTarget = i.formatKeptnV1Criteria(source, objective.SLI, warning, kind)
+
+	switch kind {
+	case criterionRelativePercent:
+		converted.Comparison = &klcv1alpha2.ObjectiveComparison{Baseline: klcv1alpha2.ComparisonBaselineEvaluation, Type: klcv1alpha2.ComparisonTypeRelative}
+	case criterionRelativeAbsolute:
+		converted.Comparison = &klcv1alpha2.ObjectiveComparison{Baseline: klcv1alpha2.ComparisonBaselineEvaluation, Type: klcv1alpha2.ComparisonTypeAbsolute}
+	}
+	return converted, true
+}
+
+// checkKeptnV1Comparison reports the comparison settings that differ from comparing with the last passed evaluation
+func (i *Importer) checkKeptnV1Comparison(source string, comparison *keptnV1Comparison) {
+	if comparison == nil {
+		return
+	}
+	if comparison.CompareWith == "several_results" || comparison.NumberOfComparisonResults > 1 {
+		i.addIssue(source, "relative objectives are compared with the last evaluation of the previous version instead of %d results", comparison.NumberOfComparisonResults)
+	}
+	if comparison.IncludeResultWithScore != "" && comparison.IncludeResultWithScore != "pass" {
+		i.addIssue(source, "relative objectives are compared with passed evaluations only instead of include_result_with_score %s", comparison.IncludeResultWithScore)
+	}
+}
+
+func convertKeptnV1TotalScore(score keptnV1TotalScore) (*klcv1alpha2.TotalScore, error) {
+	pass, err := parsePercentage(score.Pass)
+	if err != nil {
+		return nil, err
+	}
+	totalScore := &klcv1alpha2.TotalScore{PassPercentage: pass}
+	if score.Warning != "" {
+		warning, err := parsePercentage(score.Warning)
+		if err != nil {
+			return nil, err
+		}
+		totalScore.WarningPercentage = warning
+	}
+	return totalScore, nil
+}
+
+func parsePercentage(value string) (int, error) {
+	percentage, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	if err != nil || percentage < 0 || percentage > 100 {
+		return 0, fmt.Errorf("invalid percentage %q", value)
+	}
+	return int(percentage), nil
+}
+
+// keptnV1Variables maps the placeholders of Keptn v1 queries to the variables of an evaluation
+var keptnV1Variables = map[string]string{
+	"SERVICE": "{{.Workload}}",
+	"PROJECT": "{{.AppName}}",
+	"STAGE":   "{{.Namespace}}",
+}
+
+var keptnV1Placeholder = regexp.MustCompile(`\$\{?([A-Za-z_][A-Za-z0-9_.]*)\}?`)
+
+// convertKeptnV1Query sets the query of the objective, it returns false if the query cannot be imported
+func (i *Importer) convertKeptnV1Query(source string, sli string, query string, providerType string, objective *klcv1alpha2.Objective) bool {
+	unknown := []string{}
+	query = keptnV1Placeholder.ReplaceAllStringFunc(query, func(placeholder string) string {
+		name := keptnV1Placeholder.FindStringSubmatch(placeholder)[1]
+		if variable, ok := keptnV1Variables[name]; ok {
+			return variable
+		}
+		unknown = append(unknown, placeholder)
+		return placeholder
+	})
+	if len(unknown) > 0 {
+		i.addIssue(source, "the placeholders %s of SLI %s are not replaced", strings.Join(unknown, ", "), sli)
+	}
+
+	if providerType != "dynatrace" {
+		objective.Query = query
+		return true
+	}
+	return i.convertKeptnV1DynatraceQuery(source, sli, query, objective)
+}
+
+// convertKeptnV1DynatraceQuery splits Keptn v1 metrics queries, e.g.
+// MV2;MicroSecond;metricSelector=builtin:service.response.time&entitySelector=type(SERVICE),
+// into the metric selector and the settings of the dynatrace provider
+func (i *Importer) convertKeptnV1DynatraceQuery(source string, sli string, query string, objective *klcv1alpha2.Objective) bool {
+	if strings.HasPrefix(query, "MV2;") {
+		parts := strings.SplitN(query, ";", 3)
+		if len(parts) != 3 {
+			i.addIssue(source, "SLI %s is skipped, the query %q is malformed", sli, query)
+			return false
+		}
+		i.addIssue(source, "the values of SLI %s are not converted from %s", sli, parts[1])
+		query = parts[2]
+	}
+	if !strings.HasPrefix(query, "metricSelector=") {
+		if strings.Contains(query, ";") || strings.Contains(query, "?scope=") {
+			i.addIssue(source, "SLI %s is skipped, only metrics queries are supported", sli)
+			return false
+		}
+		objective.Query = query
+		return true
+	}
+
+	objective.Dynatrace = &klcv1alpha2.DynatraceQuery{}
+	for _, parameter := range strings.Split(query, "&") {
+		key, value, _ := strings.Cut(parameter, "=")
+		switch key {
+		case "metricSelector":
+			objective.Query = value
+		case "entitySelector":
+			objective.Dynatrace.EntitySelector = value
+		case "resolution":
+			objective.Dynatrace.Resolution = value
+		default:
+			i.addIssue(source, "the parameter %s of SLI %s is not imported", key, sli)
+		}
+	}
+	return true
+}
+
+type criterionKind int
+
+const (
+	criterionAbsolute criterionKind = iota
+	// criterionRelativePercent is a change compared to the previous result in percent, e.g. <=+10%
+	criterionRelativePercent
+	// criterionRelativeAbsolute is a difference to the previous result, e.g. <=+100
+	criterionRelativeAbsolute
+)
+
+type criterion struct {
+	raw      string
+	operator string
+	value    float64
+	kind     criterionKind
+}
+
+var keptnV1Criterion = regexp.MustCompile(`^\s*(<=|>=|<|>|==|=|!=)\s*([+-])?\s*([0-9]*\.?[0-9]+(?:[eE][+-]?[0-9]+)?)\s*(%)?\s*$`)
+
+func parseKeptnV1Criterion(raw string) (criterion, error) {
+	match := keptnV1Criterion.FindStringSubmatch(raw)
+	if match == nil {
+		return criterion{}, fmt.Errorf("invalid criterion %q", raw)
+	}
+	value, err := strconv.ParseFloat(match[3], 64)
+	if err != nil {
+		return criterion{}, fmt.Errorf("invalid criterion %q: %w", raw, err)
+	}
+	c := criterion{raw: raw, operator: match[1], value: value, kind: criterionAbsolute}
+	if c.operator == "=" {
+		c.operator = "=="
+	}
+	if match[2] == "-" {
+		c.value = -value
+	}
+	if match[4] == "%" {
+		c.kind = criterionRelativePercent
+	} else if match[2] != "" {
+		c.kind = criterionRelativeAbsolute
+	}
+	return c, nil
+}
+
+// parseKeptnV1Criteria parses every group of criteria, invalid criteria are reported and skipped
+func (i *Importer) parseKeptnV1Criteria(source string, sli string, groups []keptnV1Criteria) [][]criterion {
+	parsed := make([][]criterion, 0, len(groups))
+	for _, group := range groups {
+		criteria := make([]criterion, 0, len(group.Criteria))
+		for _, raw := range group.Criteria {
+			c, err := parseKeptnV1Criterion(raw)
+			if err != nil {
+				i.addIssue(source, "%s of objective %s is skipped", err.Error(), sli)
+				continue
+			}
+			criteria = append(criteria, c)
+		}
+		parsed = append(parsed, criteria)
+	}
+	return parsed
+}
+
+// selectCriterionKind returns the kind of the criteria that are imported, as the targets of an objective
+// are either checked against its value or its change. Absolute criteria are preferred.
+func selectCriterionKind(groups [][]criterion) criterionKind {
+	found := map[criterionKind]bool{}
+	for _, group := range groups {
+		for _, c := range group {
+			found[c.kind] = true
+		}
+	}
+	for _, kind := range []criterionKind{criterionAbsolute, criterionRelativePercent, criterionRelativeAbsolute} {
+		if found[kind] {
+			return kind
+		}
+	}
+	return criterionAbsolute
+}
+
+// formatKeptnV1Criteria returns the target of the criteria of the given kind. The criteria of a group
+// have to be met together, any of the groups has to be met. Criteria of other kinds are reported and skipped.
+func (i *Importer) formatKeptnV1Criteria(source string, sli string, groups [][]criterion, kind criterionKind) string {
+	expressions := []string{}
+	for _, group := range groups {
+		terms := []string{}
+		for _, c := range group {
+			if c.kind != kind {
+				i.addIssue(source, "criterion %q of objective %s is skipped, the targets of an objective are checked either against its value or its change", c.raw, sli)
+				continue
+			}
+			terms = append(terms, c.operator+strconv.FormatFloat(c.value, 'g', -1, 64))
+		}
+		if len(terms) > 0 {
+			expressions = append(expressions, strings.Join(terms, " and "))
+		}
+	}
+	if len(expressions) > 1 {
+		for j, expression := range expressions {
+			if strings.Contains(expression, " and ") {
+				expressions[j] = "(" + expression + ")"
+			}
+		}
+	}
+	return strings.Join(expressions, " or ")
+}
@@ -0,0 +1,206 @@
+package sloimport
+
+import (
+	"testing"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluation"
+	"github.com/stretchr/testify/require"
+)
+
+const testSLIFile = `
+spec_version: "1.0"
+indicators:
+  response_time_p95: histogram_quantile(0.95, sum(rate(http_duration_bucket{job="$SERVICE-$PROJECT-$STAGE"}[$DURATION_SECONDS])) by (le))
+  error_rate: sum(rate(http_errors_total{job="$SERVICE"}[5m]))
+  throughput: sum(rate(http_requests_total{job="$SERVICE"}[5m]))
+`
+
+const testSLOFile = `
+spec_version: "0.1.1"
+comparison:
+  compare_with: "several_results"
+  include_result_with_score: "pass"
+  number_of_comparison_results: 3
+filter:
+  handler: "ItemsController.addToCart"
+objectives:
+  - sli: response_time_p95
+    key_sli: true
+    weight: 2
+    pass:
+      - criteria:
+          - "<=+10%"
+          - "<600"
+    warning:
+      - criteria:
+          - "<=800"
+  - sli: error_rate
+    pass:
+      - criteria:
+          - "<=+5%"
+      - criteria:
+          - ">=-5%"
+          - "<=-1%"
+  - sli: throughput
+  - sli: memory
+    pass:
+      - criteria:
+          - "<512"
+total_score:
+  pass: "90%"
+  warning: "75%"
+`
+
+func TestImporter_AddKeptnV1(t *testing.T) {
+	importer := NewImporter(Options{Namespace: "podtato", TargetServer: "http://prometheus:9090"})
+	err := importer.AddKeptnV1(KeptnV1Files{
+		Name:         "production/carts",
+		ProviderType: "Prometheus",
+		SLIPath:      "prometheus/sli.yaml",
+		SLI:          []byte(testSLIFile),
+		SLOPath:      "slo.yaml",
+		SLO:          []byte(testSLOFile),
+	})
+	require.Nil(t, err)
+	result := importer.Result()
+
+	require.Len(t, result.Providers, 1)
+	require.Equal(t, "prometheus", result.Providers[0].Name)
+	require.Equal(t, "podtato", result.Providers[0].Namespace)
+	require.Equal(t, "http://prometheus:9090", result.Providers[0].Spec.TargetServer)
+	require.Empty(t, result.Providers[0].Spec.Type)
+
+	require.Len(t, result.Definitions, 1)
+	definition := result.Definitions[0]
+	require.Equal(t, "production-carts", definition.Name)
+	require.Equal(t, "prometheus", definition.Spec.Source)
+	require.Equal(t, &klcv1alpha2.TotalScore{PassPercentage: 90, WarningPercentage: 75}, definition.Spec.TotalScore)
+	require.Equal(t, []klcv1alpha2.Objective{
+		{
+			Name:             "response_time_p95",
+			Query:            `histogram_quantile(0.95, sum(rate(http_duration_bucket{job="{{.Workload}}-{{.AppName}}-{{.Namespace}}"}[$DURATION_SECONDS])) by (le))`,
+			EvaluationTarget: "<600",
+			WarningTarget:    "<=800",
+			Weight:           2,
+			KeySLI:           true,
+		},
+		{
+			Name:             "error_rate",
+			Query:            `sum(rate(http_errors_total{job="{{.Workload}}"}[5m]))`,
+			EvaluationTarget: "<=5 or (>=-5 and <=-1)",
+			Comparison:       &klcv1alpha2.ObjectiveComparison{Baseline: klcv1alpha2.ComparisonBaselineEvaluation, Type: klcv1alpha2.ComparisonTypeRelative},
+		},
+	}, definition.Spec.Objectives)
+	for _, objective := range definition.Spec.Objectives {
+		_, err := keptnevaluation.ParseTarget(objective.EvaluationTarget)
+		require.Nil(t, err)
+	}
+
+	issues := []string{}
+	for _, issue := range result.Issues {
+		issues = append(issues, issue.String())
+	}
+	require.Equal(t, []string{
+		"slo.yaml: the placeholders $DURATION_SECONDS of SLI response_time_p95 are not replaced",
+		`slo.yaml: criterion "<=+10%" of objective response_time_p95 is skipped, the targets of an objective are checked either against its value or its change`,
+		"slo.yaml: objective throughput is skipped, informational SLIs without pass criteria are not supported",
+		"slo.yaml: objective memory is skipped, the SLI is not defined",
+		"slo.yaml: relative objectives are compared with the last evaluation of the previous version instead of 3 results",
+		"slo.yaml: filter handler is not imported, use the variables of the evaluation in the queries instead",
+	}, issues)
+}
+
+func TestImporter_AddKeptnV1_Dynatrace(t *testing.T) {
+	sli := `
+indicators:
+  response_time: MV2;MicroSecond;metricSelector=builtin:service.response.time:merge("dt.entity.service"):percentile(95)&entitySelector=type(SERVICE),tag(keptn_service:$SERVICE)
+  throughput: builtin:service.requestCount.total:merge("dt.entity.service"):sum
+  problems: PV2;problemSelector=status(open)
+`
+	slo := `
+objectives:
+  - sli: response_time
+    pass:
+      - criteria:
+          - "<500000"
+  - sli: throughput
+    pass:
+      - criteria:
+          - ">100"
+  - sli: problems
+    pass:
+      - criteria:
+          - "==0"
+`
+	importer := NewImporter(Options{ProviderType: "dynatrace"})
+	err := importer.AddKeptnV1(KeptnV1Files{Name: "carts", SLIPath: "sli.yaml", SLI: []byte(sli), SLOPath: "slo.yaml", SLO: []byte(slo)})
+	require.Nil(t, err)
+	result := importer.Result()
+
+	require.Len(t, result.Definitions, 1)
+	require.Equal(t, []klcv1alpha2.Objective{
+		{
+			Name:             "response_time",
+			Query:            `builtin:service.response.time:merge("dt.entity.service"):percentile(95)`,
+			EvaluationTarget: "<500000",
+			Dynatrace:        &klcv1alpha2.DynatraceQuery{EntitySelector: "type(SERVICE),tag(keptn_service:{{.Workload}})"},
+		},
+		{
+			Name:             "throughput",
+			Query:            `builtin:service.requestCount.total:merge("dt.entity.service"):sum`,
+			EvaluationTarget: ">100",
+		},
+	}, result.Definitions[0].Spec.Objectives)
+	require.Len(t, result.Issues, 3)
+	require.Contains(t, result.Issues[0].Message, "not converted from MicroSecond")
+	require.Contains(t, result.Issues[1].Message, "SLI problems is skipped")
+	require.Contains(t, result.Issues[2].Message, "target server")
+}
+
+func TestImporter_AddKeptnV1_Errors(t *testing.T) {
+	importer := NewImporter(Options{})
+	err := importer.AddKeptnV1(KeptnV1Files{Name: "carts", SLIPath: "sli.yaml", SLI: []byte(testSLIFile), SLOPath: "slo.yaml", SLO: []byte(testSLOFile)})
+	require.ErrorContains(t, err, "provider type")
+
+	importer = NewImporter(Options{ProviderType: "prometheus"})
+	err = importer.AddKeptnV1(KeptnV1Files{Name: "carts", SLIPath: "sli.yaml", SLI: []byte("indicators: [a"), SLOPath: "slo.yaml", SLO: []byte(testSLOFile)})
+	require.ErrorContains(t, err, "sli.yaml")
+
+	// no objective can be imported
+	err = importer.AddKeptnV1(KeptnV1Files{Name: "carts", SLIPath: "sli.yaml", SLI: []byte(testSLIFile), SLOPath: "slo.yaml", SLO: []byte("objectives:\n  - sli: throughput\n")})
+	require.Nil(t, err)
+	result := importer.Result()
+	require.Empty(t, result.Definitions)
+	require.Empty(t, result.Providers)
+	require.Contains(t, result.Issues[len(result.Issues)-1].Message, "KeptnEvaluationDefinition carts is skipped")
+}
+
+func TestParseKeptnV1Criterion(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want criterion
+		err  bool
+	}{
+		{raw: "<600", want: criterion{operator: "<", value: 600, kind: criterionAbsolute}},
+		{raw: ">= 0.5", want: criterion{operator: ">=", value: 0.5, kind: criterionAbsolute}},
+		{raw: "=0", want: criterion{operator: "==", value: 0, kind: criterionAbsolute}},
+		{raw: "<=+10%", want: criterion{operator: "<=", value: 10, kind: criterionRelativePercent}},
+		{raw: ">-5%", want: criterion{operator: ">", value: -5, kind: criterionRelativePercent}},
+		{raw: "<+100", want: criterion{operator: "<", value: 100, kind: criterionRelativeAbsolute}},
+		{raw: "600", err: true},
+		{raw: "<abc", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseKeptnV1Criterion(tt.raw)
+			if tt.err {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			tt.want.raw = tt.raw
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package sloimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const openSLOAPIVersion = "openslo/v1"
+
+// openSLOProviderTypes maps the types of OpenSLO metric sources to the supported provider types
+var openSLOProviderTypes = map[string]string{
+	"prometheus": "prometheus",
+	"datadog":    "datadog",
+	"dynatrace":  "dynatrace",
+}
+
+type openSLOMetadata struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+type openSLODocument struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Metadata   openSLOMetadata `json:"metadata"`
+	Spec       json.RawMessage `json:"spec"`
+}
+
+type openSLOSLO struct {
+	Service         string             `json:"service,omitempty"`
+	Indicator       *openSLOSLI        `json:"indicator,omitempty"`
+	IndicatorRef    string             `json:"indicatorRef,omitempty"`
+	TimeWindow      []json.RawMessage  `json:"timeWindow,omitempty"`
+	BudgetingMethod string             `json:"budgetingMethod,omitempty"`
+	Objectives      []openSLOObjective `json:"objectives"`
+	AlertPolicies   []json.RawMessage  `json:"alertPolicies,omitempty"`
+}
+
+type openSLOObjective struct {
+	DisplayName     string   `json:"displayName,omitempty"`
+	Op              string   `json:"op,omitempty"`
+	Value           *float64 `json:"value,omitempty"`
+	Target          *float64 `json:"target,omitempty"`
+	TargetPercent   *float64 `json:"targetPercent,omitempty"`
+	CompositeWeight *float64 `json:"compositeWeight,omitempty"`
+}
+
+// openSLOSLI is an SLI document or an indicator defined inline in an SLO
+type openSLOSLI struct {
+	Metadata openSLOMetadata `json:"metadata"`
+	Spec     openSLOSLISpec  `json:"spec"`
+}
+
+type openSLOSLISpec struct {
+	ThresholdMetric *openSLOMetric      `json:"thresholdMetric,omitempty"`
+	RatioMetric     *openSLORatioMetric `json:"ratioMetric,omitempty"`
+}
+
+type openSLORatioMetric struct {
+	Counter bool            `json:"counter,omitempty"`
+	Good    *openSLOMetric  `json:"good,omitempty"`
+	Bad     *openSLOMetric  `json:"bad,omitempty"`
+	Total   *openSLOMetric  `json:"total,omitempty"`
+	Raw     json.RawMessage `json:"raw,omitempty"`
+}
+
+type openSLOMetric struct {
+	MetricSource openSLOMetricSource `json:"metricSource"`
+}
+
+type openSLOMetricSource struct {
+	MetricSourceRef string                 `json:"metricSourceRef,omitempty"`
+	Type            string                 `json:"type,omitempty"`
+	Spec            map[string]interface{} `json:"spec,omitempty"`
+}
+
+type openSLODataSource struct {
+	Type              string                 `json:"type"`
+	ConnectionDetails map[string]interface{} `json:"connectionDetails,omitempty"`
+}
+
+type openSLOSource struct {
+	source string
+	name   string
+	slo    openSLOSLO
+}
+
+// openSLODocuments holds the OpenSLO documents of every added file by kind
+type openSLODocuments struct {
+	slos        []openSLOSource
+	slis        map[string]openSLOSLI
+	dataSources map[string]openSLODataSource
+}
+
+func newOpenSLODocuments() openSLODocuments {
+	return openSLODocuments{
+		slis:        map[string]openSLOSLI{},
+		dataSources: map[string]openSLODataSource{},
+	}
+}
+
+// IsOpenSLO returns true if the given YAML contains an OpenSLO document
+func IsOpenSLO(data []byte) bool {
+	return bytes.Contains(data, []byte("openslo/"))
+}
+
+// AddOpenSLO collects the SLO, SLI and DataSource documents of the given YAML, which can contain several
+// documents. The SLOs are converted once every file has been added, as they can reference documents of other files.
+func (i *Importer) AddOpenSLO(source string, data []byte) error {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		document := openSLODocument{}
+		if err := decoder.Decode(&document); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("could not parse %s: %w", source, err)
+		}
+		if document.APIVersion == "" && document.Kind == "" {
+			continue
+		}
+		if document.APIVersion != openSLOAPIVersion {
+			i.addIssue(source, "%s %s is skipped, only %s is supported", document.Kind, document.Metadata.Name, openSLOAPIVersion)
+			continue
+		}
+		if err := i.addOpenSLODocument(source, document); err != nil {
+			return fmt.Errorf("could not parse %s %s in %s: %w", document.Kind, document.Metadata.Name, source, err)
+		}
+	}
+}
+
+func (i *Importer) addOpenSLODocument(source string, document openSLODocument) error {
+	switch document.Kind {
+	case "SLO":
+		slo := openSLOSLO{}
+		if err := json.Unmarshal(document.Spec, &slo); err != nil {
+			return err
+		}
+		i.openSLO.slos = append(i.openSLO.slos, openSLOSource{source: source, name: document.Metadata.Name, slo: slo})
+	case "SLI":
+		sli := openSLOSLI{Metadata: document.Metadata}
+		if err := json.Unmarshal(document.Spec, &sli.Spec); err != nil {
+			return err
+		}
+		i.openSLO.slis[document.Metadata.Name] = sli
+	case "DataSource":
+		dataSource := openSLODataSource{}
+		if err := json.Unmarshal(document.Spec, &dataSource); err != nil {
+			return err
+		}
+		i.openSLO.dataSources[document.Metadata.Name] = dataSource
+	default:
+		i.addIssue(source, "%s %s is not imported", document.Kind, document.Metadata.Name)
+	}
+	return nil
+}
+
+func (i *Importer) importOpenSLO() {
+	for _, slo := range i.openSLO.slos {
+		i.importOpenSLOSLO(slo)
+	}
+}
+
+// importOpenSLOSLO converts an SLO into a KeptnEvaluationDefinition with an objective for each of its objectives
+func (i *Importer) importOpenSLOSLO(slo openSLOSource) {
+	source := fmt.Sprintf("%s: SLO %s", slo.source, slo.name)
+	sli := slo.slo.Indicator
+	if sli == nil {
+		found, ok := i.openSLO.slis[slo.slo.IndicatorRef]
+		if !ok {
+			i.addIssue(source, "the SLO is skipped, SLI %q is not defined", slo.slo.IndicatorRef)
+			return
+		}
+		sli = &found
+	}
+
+	query, metricSource, ratio, ok := i.convertOpenSLOIndicator(source, *sli)
+	if !ok {
+		return
+	}
+
+	definition := i.newDefinition(sanitizeName(slo.name), "")
+	for index, objective := range slo.slo.Objectives {
+		converted, ok := i.convertOpenSLOObjective(source, objective, ratio, slo.slo.BudgetingMethod)
+		if !ok {
+			continue
+		}
+		converted.Query = query
+		converted.Name = getOpenSLOObjectiveName(slo.name, objective, index, len(slo.slo.Objectives))
+		definition.Spec.Objectives = append(definition.Spec.Objectives, converted)
+	}
+	if len(definition.Spec.Objectives) == 0 {
+		i.addIssue(source, "no objective could be imported, KeptnEvaluationDefinition %s is skipped", definition.Name)
+		return
+	}
+
+	if len(slo.slo.TimeWindow) > 0 {
+		i.addIssue(source, "the time window is not imported, the objectives are checked against the value at the time of the evaluation")
+	}
+	if len(slo.slo.AlertPolicies) > 0 {
+		i.addIssue(source, "alert policies are not imported")
+	}
+	definition.Spec.Source = i.importOpenSLOProvider(source, metricSource)
+	i.addDefinition(source, definition)
+}
+
+func getOpenSLOObjectiveName(sloName string, objective openSLOObjective, index int, count int) string {
+	if objective.DisplayName != "" {
+		return objective.DisplayName
+	}
+	if count == 1 {
+		return sloName
+	}
+	return fmt.Sprintf("%s-%d", sloName, index+1)
+}
+
+// convertOpenSLOIndicator returns the query of the SLI and its metric source. Ratio metrics are divided
+// by the provider, the returned bool is true if the query returns the ratio of good events.
+func (i *Importer) convertOpenSLOIndicator(source string, sli openSLOSLI) (string, openSLOMetricSource, bool, bool) {
+	if sli.Spec.ThresholdMetric != nil {
+		query, metricSource, ok := i.getOpenSLOQuery(source, *sli.Spec.ThresholdMetric)
+		return query, metricSource, false, ok
+	}
+
+	ratio := sli.Spec.RatioMetric
+	if ratio == nil {
+		i.addIssue(source, "the SLO is skipped, SLI %s has neither a threshold nor a ratio metric", sli.Metadata.Name)
+		return "", openSLOMetricSource{}, false, false
+	}
+	numerator := ratio.Good
+	if numerator == nil {
+		numerator = ratio.Bad
+	}
+	if len(ratio.Raw) > 0 || numerator == nil || ratio.Total == nil {
+		i.addIssue(source, "the SLO is skipped, only ratio metrics with good or bad and total queries are supported")
+		return "", openSLOMetricSource{}, false, false
+	}
+	numeratorQuery, metricSource, ok := i.getOpenSLOQuery(source, *numerator)
+	if !ok {
+		return "", openSLOMetricSource{}, false, false
+	}
+	totalQuery, totalSource, ok := i.getOpenSLOQuery(source, *ratio.Total)
+	if !ok {
+		return "", openSLOMetricSource{}, false, false
+	}
+	if metricSource.Type != "prometheus" || totalSource.Type != "prometheus" || metricSource.MetricSourceRef != totalSource.MetricSourceRef {
+		i.addIssue(source, "the SLO is skipped, ratio metrics are only supported with a single prometheus metric source")
+		return "", openSLOMetricSource{}, false, false
+	}
+	if ratio.Counter {
+		i.addIssue(source, "the counters of the ratio metric are divided as they are, they might have to be wrapped in increase()")
+	}
+	return fmt.Sprintf("(%s) / (%s)", numeratorQuery, totalQuery), metricSource, ratio.Good != nil, true
+}
+
+// getOpenSLOQuery returns the query of the metric and its source with the provider type
+// of the referenced DataSource or the inline type
+func (i *Importer) getOpenSLOQuery(source string, metric openSLOMetric) (string, openSLOMetricSource, bool) {
+	metricSource := metric.MetricSource
+	sourceType := metricSource.Type
+	if metricSource.MetricSourceRef != "" {
+		dataSource, ok := i.openSLO.dataSources[metricSource.MetricSourceRef]
+		if !ok {
+			i.addIssue(source, "the SLO is skipped, DataSource %q is not defined", metricSource.MetricSourceRef)
+			return "", metricSource, false
+		}
+		sourceType = dataSource.Type
+	}
+	providerType, ok := openSLOProviderTypes[strings.ToLower(sourceType)]
+	if !ok {
+		i.addIssue(source, "the SLO is skipped, metric sources of type %q are not supported", sourceType)
+		return "", metricSource, false
+	}
+	metricSource.Type = providerType
+
+	key := "query"
+	if providerType == "dynatrace" {
+		key = "metricSelector"
+	}
+	query, ok := metricSource.Spec[key].(string)
+	if !ok || query == "" {
+		i.addIssue(source, "the SLO is skipped, the metric source has no %s", key)
+		return "", metricSource, false
+	}
+	for _, other := range sortedKeys(metricSource.Spec) {
+		if other != key {
+			i.addIssue(source, "the field %s of the metric source is not imported", other)
+		}
+	}
+	return query, metricSource, true
+}
+
+// importOpenSLOProvider adds the provider of the metric source and returns its name
+func (i *Importer) importOpenSLOProvider(source string, metricSource openSLOMetricSource) string {
+	if metricSource.MetricSourceRef == "" {
+		i.addProvider(source, i.newProvider(source, metricSource.Type, metricSource.Type, i.options.TargetServer))
+		return metricSource.Type
+	}
+
+	name := sanitizeName(metricSource.MetricSourceRef)
+	dataSource := i.openSLO.dataSources[metricSource.MetricSourceRef]
+	targetServer := ""
+	for _, key := range sortedKeys(dataSource.ConnectionDetails) {
+		value, isString := dataSource.ConnectionDetails[key].(string)
+		switch {
+		case isString && targetServer == "" && (key == "url" || key == "address" || key == "endpoint"):
+			targetServer = value
+		default:
+			i.addIssue(source, "the connection detail %s of DataSource %s is not imported, credentials have to be configured with a secretKeyRef", key, metricSource.MetricSourceRef)
+		}
+	}
+	if targetServer == "" {
+		targetServer = i.options.TargetServer
+	}
+	i.addProvider(source, i.newProvider(source, name, metricSource.Type, targetServer))
+	return name
+}
+
+var openSLOOperators = map[string]string{
+	"lt":  "<",
+	"lte": "<=",
+	"gt":  ">",
+	"gte": ">=",
+}
+
+// convertOpenSLOObjective returns the objective with the target of the OpenSLO objective. The targets of
+// threshold metrics are checked against the value of the query, the ones of ratio metrics against the ratio.
+func (i *Importer) convertOpenSLOObjective(source string, objective openSLOObjective, goodRatio bool, budgetingMethod string) (klcv1alpha2.Objective, bool) {
+	converted := klcv1alpha2.Objective{}
+	if objective.CompositeWeight != nil {
+		converted.Weight = int(math.Round(*objective.CompositeWeight))
+		if float64(converted.Weight) != *objective.CompositeWeight || converted.Weight < 1 {
+			i.addIssue(source, "the composite weight %s is rounded to a weight of %d", formatNumber(*objective.CompositeWeight), converted.GetWeight())
+		}
+	}
+
+	target := objective.Target
+	if target == nil && objective.TargetPercent != nil {
+		ratio := *objective.TargetPercent / 100
+		target = &ratio
+	}
+
+	if objective.Op == "" && objective.Value == nil {
+		if target == nil {
+			i.addIssue(source, "objective %s is skipped, it has neither a threshold nor a target", objective.DisplayName)
+			return converted, false
+		}
+		// the target is the ratio of good events
+		if goodRatio {
+			converted.EvaluationTarget = ">=" + formatNumber(*target)
+		} else {
+			// rounded to hide the floating point error of the subtraction, e.g. of 1-0.99
+			converted.EvaluationTarget = "<=" + formatNumber(math.Round((1-*target)*1e12)/1e12)
+		}
+		return converted, true
+	}
+
+	operator, ok := openSLOOperators[objective.Op]
+	if !ok || objective.Value == nil {
+		i.addIssue(source, "objective %s is skipped, the operator %q or the value is not valid", objective.DisplayName, objective.Op)
+		return converted, false
+	}
+	converted.EvaluationTarget = operator + formatNumber(*objective.Value)
+	if target != nil {
+		i.addIssue(source, "the %s target %s of objective %s is not imported, its threshold is checked against the value at the time of the evaluation",
+			budgetingMethod, formatNumber(*target), objective.DisplayName)
+	}
+	return converted, true
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,210 @@
+package sloimport
+
+import (
+	"testing"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+)
+
+const testDataSources = `
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: Cortex
+spec:
+  type: Prometheus
+  connectionDetails:
+    url: http://cortex:9009/prometheus
+    token: abc
+---
+apiVersion: openslo/v1
+kind: DataSource
+metadata:
+  name: cloudwatch
+spec:
+  type: CloudWatch
+`
+
+const testSLOs = `
+apiVersion: openslo/v1
+kind: SLI
+metadata:
+  name: availability
+spec:
+  ratioMetric:
+    good:
+      metricSource:
+        metricSourceRef: Cortex
+        spec:
+          query: sum(rate(http_requests_total{code!~"5.."}[5m]))
+    total:
+      metricSource:
+        metricSourceRef: Cortex
+        spec:
+          query: sum(rate(http_requests_total[5m]))
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-availability
+spec:
+  service: checkout
+  indicatorRef: availability
+  budgetingMethod: Occurrences
+  objectives:
+    - displayName: availability
+      targetPercent: 99.5
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: checkout-latency
+spec:
+  service: checkout
+  indicator:
+    metadata:
+      name: latency
+    spec:
+      thresholdMetric:
+        metricSource:
+          type: Prometheus
+          spec:
+            query: histogram_quantile(0.99, sum(rate(latency_bucket[5m])) by (le))
+  timeWindow:
+    - duration: 28d
+      isRolling: true
+  budgetingMethod: Timeslices
+  objectives:
+    - displayName: fast
+      op: lte
+      value: 250
+      target: 0.99
+    - op: lt
+      value: 1000
+      compositeWeight: 2
+---
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: errors
+spec:
+  indicator:
+    metadata:
+      name: errors
+    spec:
+      thresholdMetric:
+        metricSource:
+          metricSourceRef: cloudwatch
+          spec:
+            metricName: errors
+  objectives:
+    - op: lt
+      value: 1
+---
+apiVersion: openslo/v1alpha
+kind: SLO
+metadata:
+  name: legacy
+---
+apiVersion: openslo/v1
+kind: Service
+metadata:
+  name: checkout
+`
+
+func TestImporter_AddOpenSLO(t *testing.T) {
+	importer := NewImporter(Options{Namespace: "podtato"})
+	// the SLOs reference data sources of another file
+	require.Nil(t, importer.AddOpenSLO("slos.yaml", []byte(testSLOs)))
+	require.Nil(t, importer.AddOpenSLO("datasources.yaml", []byte(testDataSources)))
+	result := importer.Result()
+
+	require.Len(t, result.Providers, 2)
+	require.Equal(t, "cortex", result.Providers[0].Name)
+	require.Equal(t, klcv1alpha2.KeptnEvaluationProviderSpec{Type: "prometheus", TargetServer: "http://cortex:9009/prometheus"}, result.Providers[0].Spec)
+	require.Equal(t, "prometheus", result.Providers[1].Name)
+	require.Empty(t, result.Providers[1].Spec.Type)
+
+	require.Len(t, result.Definitions, 2)
+	require.Equal(t, "checkout-availability", result.Definitions[0].Name)
+	require.Equal(t, "cortex", result.Definitions[0].Spec.Source)
+	require.Equal(t, []klcv1alpha2.Objective{
+		{
+			Name:             "availability",
+			Query:            `(sum(rate(http_requests_total{code!~"5.."}[5m]))) / (sum(rate(http_requests_total[5m])))`,
+			EvaluationTarget: ">=0.995",
+		},
+	}, result.Definitions[0].Spec.Objectives)
+
+	require.Equal(t, "checkout-latency", result.Definitions[1].Name)
+	require.Equal(t, "prometheus", result.Definitions[1].Spec.Source)
+	require.Equal(t, []klcv1alpha2.Objective{
+		{
+			Name:             "fast",
+			Query:            "histogram_quantile(0.99, sum(rate(latency_bucket[5m])) by (le))",
+			EvaluationTarget: "<=250",
+		},
+		{
+			Name:             "checkout-latency-2",
+			Query:            "histogram_quantile(0.99, sum(rate(latency_bucket[5m])) by (le))",
+			EvaluationTarget: "<1000",
+			Weight:           2,
+		},
+	}, result.Definitions[1].Spec.Objectives)
+
+	issues := []string{}
+	for _, issue := range result.Issues {
+		issues = append(issues, issue.String())
+	}
+	require.Equal(t, []string{
+		"slos.yaml: SLO legacy is skipped, only openslo/v1 is supported",
+		"slos.yaml: Service checkout is not imported",
+		"slos.yaml: SLO checkout-availability: the connection detail token of DataSource Cortex is not imported, credentials have to be configured with a secretKeyRef",
+		"slos.yaml: SLO checkout-latency: the Timeslices target 0.99 of objective fast is not imported, its threshold is checked against the value at the time of the evaluation",
+		"slos.yaml: SLO checkout-latency: the time window is not imported, the objectives are checked against the value at the time of the evaluation",
+		"slos.yaml: SLO checkout-latency: the target server of KeptnEvaluationProvider prometheus is not known, replace the placeholder <target-server>",
+		`slos.yaml: SLO errors: the SLO is skipped, metric sources of type "CloudWatch" are not supported`,
+	}, issues)
+}
+
+func TestImporter_AddOpenSLO_BadRatio(t *testing.T) {
+	slo := `
+apiVersion: openslo/v1
+kind: SLO
+metadata:
+  name: errors
+spec:
+  indicator:
+    metadata:
+      name: errors
+    spec:
+      ratioMetric:
+        bad:
+          metricSource:
+            type: Prometheus
+            spec:
+              query: sum(rate(http_requests_total{code=~"5.."}[5m]))
+        total:
+          metricSource:
+            type: Prometheus
+            spec:
+              query: sum(rate(http_requests_total[5m]))
+  objectives:
+    - target: 0.99
+`
+	importer := NewImporter(Options{TargetServer: "http://prometheus:9090"})
+	require.Nil(t, importer.AddOpenSLO("slo.yaml", []byte(slo)))
+	result := importer.Result()
+
+	require.Len(t, result.Definitions, 1)
+	require.Equal(t, "<=0.01", result.Definitions[0].Spec.Objectives[0].EvaluationTarget)
+	require.Equal(t, "http://prometheus:9090", result.Providers[0].Spec.TargetServer)
+	require.Empty(t, result.Issues)
+}
+
+func TestImporter_AddOpenSLO_InvalidYAML(t *testing.T) {
+	importer := NewImporter(Options{})
+	err := importer.AddOpenSLO("slo.yaml", []byte("apiVersion: openslo/v1\nkind: SLO\nspec: [a"))
+	require.ErrorContains(t, err, "slo.yaml")
+}
@@ -0,0 +1,189 @@
+package webhooks
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common"
+	controllererrors "github.com/keptn/lifecycle-toolkit/operator/controllers/errors"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluation"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluation/providers"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-lifecycle-keptn-sh-v1alpha2-keptnevaluationdefinition,mutating=false,failurePolicy=fail,groups=lifecycle.keptn.sh,resources=keptnevaluationdefinitions;clusterkeptnevaluationdefinitions,verbs=create;update,versions=v1alpha2,name=vkeptnevaluationdefinition.keptn.sh,admissionReviewVersions=v1,sideEffects=None
+
+// EvaluationDefinitionValidatingWebhook rejects KeptnEvaluationDefinitions and ClusterKeptnEvaluationDefinitions
+// that would fail every evaluation
+type EvaluationDefinitionValidatingWebhook struct {
+	Client  client.Client
+	decoder *admission.Decoder
+	Log     logr.Logger
+}
+
+// Handle validates the objectives and the referenced providers of incoming KeptnEvaluationDefinitions
+func (a *EvaluationDefinitionValidatingWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Kind.Kind == "ClusterKeptnEvaluationDefinition" {
+		return a.handleClusterDefinition(req)
+	}
+
+	definition := &klcv1alpha2.KeptnEvaluationDefinition{}
+	if err := a.decoder.Decode(req, definition); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	errs := validateObjectives(definition.Spec.Objectives, req.Namespace, field.NewPath("spec", "objectives"))
+	// the source of the definition is only checked if an objective is queried by it
+	if definition.Spec.UsesSource() {
+		sourceErrs, err := a.validateSource(ctx, definition.Spec.Source, req.Namespace, field.NewPath("spec", "source"))
+		if err != nil {
+			a.Log.Error(err, "Could not get KeptnEvaluationProvider", "name", definition.Spec.Source, "namespace", req.Namespace)
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		errs = append(errs, sourceErrs...)
+	}
+
+	objectiveSourceErrs, err := a.validateObjectiveSources(ctx, definition, req.Namespace, field.NewPath("spec", "objectives"))
+	if err != nil {
+		a.Log.Error(err, "Could not get KeptnEvaluationProvider", "namespace", req.Namespace)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	errs = append(errs, objectiveSourceErrs...)
+
+	if len(errs) > 0 {
+		a.Log.Info("Rejected KeptnEvaluationDefinition", "name", req.Name, "namespace", req.Namespace, "errors", errs.ToAggregate().Error())
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// handleClusterDefinition validates the objectives of incoming ClusterKeptnEvaluationDefinitions.
+// Their sources are resolved in the namespace of every evaluation, so they are not checked.
+func (a *EvaluationDefinitionValidatingWebhook) handleClusterDefinition(req admission.Request) admission.Response {
+	definition := &klcv1alpha2.ClusterKeptnEvaluationDefinition{}
+	if err := a.decoder.Decode(req, definition); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	errs := validateObjectives(definition.Spec.Objectives, "", field.NewPath("spec", "objectives"))
+	if definition.Spec.UsesSource() && definition.Spec.Source == "" {
+		errs = append(errs, field.Required(field.NewPath("spec", "source"), "the source is required for objectives without their own source"))
+	}
+	if len(errs) > 0 {
+		a.Log.Info("Rejected ClusterKeptnEvaluationDefinition", "name", req.Name, "errors", errs.ToAggregate().Error())
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder.
+func (a *EvaluationDefinitionValidatingWebhook) InjectDecoder(d *admission.Decoder) error {
+	a.decoder = d
+	return nil
+}
+
+// validateSource checks that the provider referenced by the definition, or the cluster provider of the same name,
+// exists, may be used in the namespace and is valid
+func (a *EvaluationDefinitionValidatingWebhook) validateSource(ctx context.Context, source string, namespace string, path *field.Path) (field.ErrorList, error) {
+	if source == "" {
+		return field.ErrorList{field.Required(path, "the source is required for objectives without their own source")}, nil
+	}
+	// the secrets of the provider are not read, so no secret namespace is needed
+	provider, err := common.GetEvaluationProvider(ctx, a.Client, source, namespace, "")
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return field.ErrorList{field.NotFound(path, source)}, nil
+		}
+		if goerrors.Is(err, controllererrors.ErrClusterProviderNotAllowed) {
+			return field.ErrorList{field.Forbidden(path, err.Error())}, nil
+		}
+		return nil, err
+	}
+	errs := field.ErrorList{}
+	for _, err := range validateEvaluationProvider(provider, field.NewPath("spec")) {
+		errs = append(errs, field.Invalid(path, source, fmt.Sprintf("the provider is invalid: %s", err.Error())))
+	}
+	return errs, nil
+}
+
+// validateObjectiveSources checks the providers referenced by objectives overriding the source of the definition,
+// every distinct provider is fetched only once
+func (a *EvaluationDefinitionValidatingWebhook) validateObjectiveSources(ctx context.Context, definition *klcv1alpha2.KeptnEvaluationDefinition, namespace string, path *field.Path) (field.ErrorList, error) {
+	errs := field.ErrorList{}
+	valid := map[string]bool{}
+	if definition.Spec.UsesSource() {
+		// the source of the definition has already been checked
+		valid[definition.Spec.Source] = true
+	}
+	for i, objective := range definition.Spec.Objectives {
+		if objective.Source == "" {
+			continue
+		}
+		sourcePath := path.Index(i).Child("source")
+		if isValid, ok := valid[objective.Source]; ok {
+			if !isValid {
+				errs = append(errs, field.Invalid(sourcePath, objective.Source, "the provider is not found or invalid"))
+			}
+			continue
+		}
+		sourceErrs, err := a.validateSource(ctx, objective.Source, namespace, sourcePath)
+		if err != nil {
+			return nil, err
+		}
+		valid[objective.Source] = len(sourceErrs) == 0
+		errs = append(errs, sourceErrs...)
+	}
+	return errs, nil
+}
+
+// validateObjectives checks that the names of the objectives are unique, their targets can be parsed
+// and their queries only read what the evaluations in the namespace may read
+func validateObjectives(objectives []klcv1alpha2.Objective, namespace string, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	names := map[string]bool{}
+	for i, objective := range objectives {
+		objectivePath := path.Index(i)
+		if names[objective.Name] {
+			errs = append(errs, field.Duplicate(objectivePath.Child("name"), objective.Name))
+		}
+		names[objective.Name] = true
+
+		if _, err := keptnevaluation.ParseTarget(objective.EvaluationTarget); err != nil {
+			errs = append(errs, field.Invalid(objectivePath.Child("evaluationTarget"), objective.EvaluationTarget, err.Error()))
+		}
+		if objective.WarningTarget != "" {
+			if _, err := keptnevaluation.ParseTarget(objective.WarningTarget); err != nil {
+				errs = append(errs, field.Invalid(objectivePath.Child("warningTarget"), objective.WarningTarget, err.Error()))
+			}
+		}
+		if err := keptnevaluation.ValidateComparison(objective); err != nil {
+			errs = append(errs, field.Invalid(objectivePath.Child("query"), objective.Query, err.Error()))
+		}
+		if objective.Kubernetes != nil {
+			errs = append(errs, validateKubernetesQuery(objective.Kubernetes, namespace, objectivePath.Child("kubernetes"))...)
+		}
+		if objective.Anomaly != nil && objective.MultiSeries.IsPerSeries() {
+			errs = append(errs, field.Invalid(objectivePath.Child("anomaly"), objective.Anomaly, "anomaly detection does not support checking every series"))
+		}
+	}
+	return errs
+}
+
+// validateKubernetesQuery checks that the query does not read sensitive kinds and only reads from the namespace
+// of the definition. Cluster definitions are used in the namespace of every evaluation, so they cannot set a namespace.
+func validateKubernetesQuery(query *klcv1alpha2.KubernetesQuery, namespace string, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if err := providers.CheckKubernetesKind(query); err != nil {
+		errs = append(errs, field.Forbidden(path.Child("kind"), err.Error()))
+	}
+	if query.Namespace != "" && query.Namespace != namespace {
+		errs = append(errs, field.Forbidden(path.Child("namespace"), controllererrors.ErrKubernetesNamespaceNotAllowed.Error()))
+	}
+	return errs
+}
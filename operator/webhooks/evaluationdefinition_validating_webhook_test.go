@@ -0,0 +1,240 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestEvaluationDefinitionValidatingWebhook_Handle(t *testing.T) {
+	prometheus := &klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus", Namespace: "default"},
+		Spec:       klcv1alpha2.KeptnEvaluationProviderSpec{TargetServer: "http://prometheus:9090"},
+	}
+	invalidProvider := &klcv1alpha2.KeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "dynatrace", Namespace: "default"},
+		Spec:       klcv1alpha2.KeptnEvaluationProviderSpec{TargetServer: "https://abc.live.dynatrace.com"},
+	}
+	clusterProvider := &klcv1alpha2.ClusterKeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared-prometheus"},
+		Spec: klcv1alpha2.ClusterKeptnEvaluationProviderSpec{
+			KeptnEvaluationProviderSpec: klcv1alpha2.KeptnEvaluationProviderSpec{Type: "prometheus", TargetServer: "http://prometheus:9090"},
+		},
+	}
+	restrictedClusterProvider := &klcv1alpha2.ClusterKeptnEvaluationProvider{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted-prometheus"},
+		Spec: klcv1alpha2.ClusterKeptnEvaluationProviderSpec{
+			KeptnEvaluationProviderSpec: klcv1alpha2.KeptnEvaluationProviderSpec{Type: "prometheus", TargetServer: "http://prometheus:9090"},
+			NamespaceSelector:           &metav1.LabelSelector{MatchLabels: map[string]string{"team": "checkout"}},
+		},
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"team": "payment"}}}
+
+	tests := []struct {
+		name       string
+		source     string
+		objectives []klcv1alpha2.Objective
+		allowed    bool
+		reasons    []string
+	}{
+		{
+			name:   "valid",
+			source: "prometheus",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "error-rate", Query: "rate(errors[5m])", EvaluationTarget: "<0.1", WarningTarget: "<0.2"},
+				{Name: "response-time", Query: "response_time", EvaluationTarget: "between 0 and 500"},
+			},
+			allowed: true,
+		},
+		{
+			name:   "invalid targets",
+			source: "prometheus",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "error-rate", Query: "rate(errors[5m])", EvaluationTarget: "=>5"},
+				{Name: "response-time", Query: "response_time", EvaluationTarget: "<500", WarningTarget: "less than 600"},
+			},
+			reasons: []string{"spec.objectives[0].evaluationTarget", "spec.objectives[1].warningTarget"},
+		},
+		{
+			name:   "duplicate names",
+			source: "prometheus",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "error-rate", Query: "rate(errors[5m])", EvaluationTarget: "<0.1"},
+				{Name: "error-rate", Query: "rate(errors[1m])", EvaluationTarget: "<0.1"},
+			},
+			reasons: []string{"spec.objectives[1].name: Duplicate value"},
+		},
+		{
+			name:   "anomaly detection of every series",
+			source: "prometheus",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "latency", Query: "latency", EvaluationTarget: "<500", MultiSeries: klcv1alpha2.MultiSeriesAll, Anomaly: &klcv1alpha2.AnomalyDetection{}},
+			},
+			reasons: []string{"spec.objectives[0].anomaly"},
+		},
+		{
+			name:   "relative objectives",
+			source: "prometheus",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "templated", Query: `latency{version="{{ .WorkloadVersion }}"}`, EvaluationTarget: "<10", Comparison: &klcv1alpha2.ObjectiveComparison{}},
+				{Name: "previous-query", Query: `latency{version="2.0.0"}`, EvaluationTarget: "<10", Comparison: &klcv1alpha2.ObjectiveComparison{PreviousVersionQuery: `latency{version="1.0.0"}`}},
+				{Name: "evaluation-baseline", Query: "latency", EvaluationTarget: "<10", Comparison: &klcv1alpha2.ObjectiveComparison{Baseline: klcv1alpha2.ComparisonBaselineEvaluation}},
+				{Name: "untemplated", Query: `latency{version="2.0.0"}`, EvaluationTarget: "<10", Comparison: &klcv1alpha2.ObjectiveComparison{}},
+			},
+			reasons: []string{"spec.objectives[3].query: Invalid value", "previousVersionQuery"},
+		},
+		{
+			name:   "kubernetes queries",
+			source: "prometheus",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "pods", Query: "count", EvaluationTarget: "<10", Kubernetes: &klcv1alpha2.KubernetesQuery{Kind: "Pod", Namespace: "default"}},
+				{Name: "secrets", Query: "field", EvaluationTarget: "<10", Kubernetes: &klcv1alpha2.KubernetesQuery{APIVersion: "v1", Kind: "Secret", Name: "token", JSONPath: ".data.value"}},
+				{Name: "other-namespace", Query: "count", EvaluationTarget: "<10", Kubernetes: &klcv1alpha2.KubernetesQuery{Kind: "Pod", Namespace: "kube-system"}},
+			},
+			reasons: []string{"spec.objectives[1].kubernetes.kind: Forbidden", "spec.objectives[2].kubernetes.namespace: Forbidden"},
+		},
+		{
+			name:   "unknown source",
+			source: "prometheus-prod",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "error-rate", Query: "rate(errors[5m])", EvaluationTarget: "<0.1"},
+			},
+			reasons: []string{"spec.source: Not found"},
+		},
+		{
+			name:   "invalid provider",
+			source: "dynatrace",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "error-rate", Query: "builtin:service.errors.total.rate", EvaluationTarget: "<0.1"},
+			},
+			reasons: []string{"spec.source: Invalid value", "API token"},
+		},
+		{
+			name:   "cluster provider",
+			source: "shared-prometheus",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "error-rate", Query: "rate(errors[5m])", EvaluationTarget: "<0.1"},
+			},
+			allowed: true,
+		},
+		{
+			name:   "cluster provider not selecting the namespace",
+			source: "restricted-prometheus",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "error-rate", Query: "rate(errors[5m])", EvaluationTarget: "<0.1"},
+			},
+			reasons: []string{"spec.source: Forbidden"},
+		},
+		{
+			name:   "objective sources",
+			source: "prometheus",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "error-rate", Query: "rate(errors[5m])", EvaluationTarget: "<0.1", Source: "prometheus"},
+				{Name: "response-time", Query: "response_time", EvaluationTarget: "<500", Source: "prometheus-prod"},
+				{Name: "failure-rate", Query: "builtin:service.errors.total.rate", EvaluationTarget: "<0.1", Source: "dynatrace"},
+				{Name: "failure-count", Query: "builtin:service.errors.total.count", EvaluationTarget: "<10", Source: "dynatrace"},
+			},
+			reasons: []string{"spec.objectives[1].source: Not found", "spec.objectives[2].source: Invalid value", "spec.objectives[3].source: Invalid value"},
+		},
+		{
+			name:   "unused source",
+			source: "prometheus-prod",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "error-rate", Query: "rate(errors[5m])", EvaluationTarget: "<0.1", Source: "prometheus"},
+			},
+			allowed: true,
+		},
+		{
+			name: "objective sources without source",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "error-rate", Query: "rate(errors[5m])", EvaluationTarget: "<0.1", Source: "prometheus"},
+			},
+			allowed: true,
+		},
+		{
+			name: "missing source",
+			objectives: []klcv1alpha2.Objective{
+				{Name: "error-rate", Query: "rate(errors[5m])", EvaluationTarget: "<0.1", Source: "prometheus"},
+				{Name: "response-time", Query: "response_time", EvaluationTarget: "<500"},
+			},
+			reasons: []string{"spec.source: Required value"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient, err := fake.NewClient(prometheus, invalidProvider, clusterProvider, restrictedClusterProvider, namespace)
+			require.Nil(t, err)
+			a := &EvaluationDefinitionValidatingWebhook{
+				Client:  fakeClient,
+				decoder: newTestDecoder(t),
+				Log:     ctrl.Log.WithName("testytest"),
+			}
+			definition := &klcv1alpha2.KeptnEvaluationDefinition{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "lifecycle.keptn.sh/v1alpha2", Kind: "KeptnEvaluationDefinition"},
+				ObjectMeta: metav1.ObjectMeta{Name: "my-definition", Namespace: "default"},
+				Spec: klcv1alpha2.KeptnEvaluationDefinitionSpec{
+					Source:     tt.source,
+					Objectives: tt.objectives,
+				},
+			}
+			resp := a.Handle(context.TODO(), newAdmissionRequest(t, definition))
+			require.Equal(t, tt.allowed, resp.Allowed)
+			for _, reason := range tt.reasons {
+				require.Contains(t, string(resp.Result.Reason), reason)
+			}
+		})
+	}
+}
+
+func TestEvaluationDefinitionValidatingWebhook_Handle_Cluster(t *testing.T) {
+	fakeClient, err := fake.NewClient()
+	require.Nil(t, err)
+	a := &EvaluationDefinitionValidatingWebhook{
+		Client:  fakeClient,
+		decoder: newTestDecoder(t),
+		Log:     ctrl.Log.WithName("testytest"),
+	}
+	definition := &klcv1alpha2.ClusterKeptnEvaluationDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "lifecycle.keptn.sh/v1alpha2", Kind: "ClusterKeptnEvaluationDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: "my-definition"},
+		Spec: klcv1alpha2.KeptnEvaluationDefinitionSpec{
+			// the source is resolved in the namespace of every evaluation
+			Source: "prometheus",
+			Objectives: []klcv1alpha2.Objective{
+				{Name: "error-rate", Query: "rate(errors[5m])", EvaluationTarget: "<0.1"},
+			},
+		},
+	}
+	resp := a.Handle(context.TODO(), newAdmissionRequest(t, definition))
+	require.True(t, resp.Allowed)
+
+	definition.Spec.Objectives[0].EvaluationTarget = "=>5"
+	resp = a.Handle(context.TODO(), newAdmissionRequest(t, definition))
+	require.False(t, resp.Allowed)
+	require.Contains(t, string(resp.Result.Reason), "spec.objectives[0].evaluationTarget")
+
+	// the objects are read from the namespace of every evaluation
+	definition.Spec.Objectives[0].EvaluationTarget = "<0.1"
+	definition.Spec.Objectives[0].Kubernetes = &klcv1alpha2.KubernetesQuery{Kind: "Pod", Namespace: "default"}
+	resp = a.Handle(context.TODO(), newAdmissionRequest(t, definition))
+	require.False(t, resp.Allowed)
+	require.Contains(t, string(resp.Result.Reason), "spec.objectives[0].kubernetes.namespace")
+
+	// objectives without their own source need the source of the definition
+	definition.Spec.Objectives[0].Kubernetes = nil
+	definition.Spec.Source = ""
+	resp = a.Handle(context.TODO(), newAdmissionRequest(t, definition))
+	require.False(t, resp.Allowed)
+	require.Contains(t, string(resp.Result.Reason), "spec.source: Required value")
+
+	definition.Spec.Objectives[0].Source = "prometheus"
+	resp = a.Handle(context.TODO(), newAdmissionRequest(t, definition))
+	require.True(t, resp.Allowed)
+}
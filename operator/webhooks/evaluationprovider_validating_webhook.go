@@ -0,0 +1,127 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/keptnevaluation/providers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-lifecycle-keptn-sh-v1alpha2-keptnevaluationprovider,mutating=false,failurePolicy=fail,groups=lifecycle.keptn.sh,resources=keptnevaluationproviders;clusterkeptnevaluationproviders,verbs=create;update,versions=v1alpha2,name=vkeptnevaluationprovider.keptn.sh,admissionReviewVersions=v1,sideEffects=None
+
+// EvaluationProviderValidatingWebhook rejects KeptnEvaluationProviders and ClusterKeptnEvaluationProviders
+// that cannot be used by evaluations
+type EvaluationProviderValidatingWebhook struct {
+	decoder *admission.Decoder
+	Log     logr.Logger
+}
+
+// Handle validates the type, target server and credentials of incoming KeptnEvaluationProviders
+// and ClusterKeptnEvaluationProviders
+func (a *EvaluationProviderValidatingWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var errs field.ErrorList
+	if req.Kind.Kind == "ClusterKeptnEvaluationProvider" {
+		clusterProvider := &klcv1alpha2.ClusterKeptnEvaluationProvider{}
+		if err := a.decoder.Decode(req, clusterProvider); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		errs = validateClusterEvaluationProvider(clusterProvider, field.NewPath("spec"))
+	} else {
+		provider := &klcv1alpha2.KeptnEvaluationProvider{}
+		if err := a.decoder.Decode(req, provider); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		errs = validateEvaluationProvider(provider, field.NewPath("spec"))
+	}
+
+	if len(errs) > 0 {
+		a.Log.Info("Rejected "+req.Kind.Kind, "name", req.Name, "namespace", req.Namespace, "errors", errs.ToAggregate().Error())
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder.
+func (a *EvaluationProviderValidatingWebhook) InjectDecoder(d *admission.Decoder) error {
+	a.decoder = d
+	return nil
+}
+
+// validateClusterEvaluationProvider checks the settings of the cluster provider like the ones of a namespaced provider
+// and that its namespace selector can be parsed
+func validateClusterEvaluationProvider(clusterProvider *klcv1alpha2.ClusterKeptnEvaluationProvider, path *field.Path) field.ErrorList {
+	provider := clusterProvider.ToEvaluationProvider("")
+	errs := validateEvaluationProvider(&provider, path)
+	if clusterProvider.Spec.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(clusterProvider.Spec.NamespaceSelector); err != nil {
+			errs = append(errs, field.Invalid(path.Child("namespaceSelector"), clusterProvider.Spec.NamespaceSelector, err.Error()))
+		}
+	}
+	return errs
+}
+
+// validateEvaluationProvider checks that the type of the provider is supported and the settings it requires are set
+func validateEvaluationProvider(provider *klcv1alpha2.KeptnEvaluationProvider, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	spec := provider.Spec
+	providerType := strings.ToLower(provider.GetType())
+	if !providers.IsSupported(providerType) {
+		if spec.Type == "" {
+			return append(errs, field.Required(path.Child("type"), "the name of the provider is not a supported type, the type has to be set"))
+		}
+		return append(errs, field.Invalid(path.Child("type"), spec.Type, "the type of provider is not supported"))
+	}
+
+	if providerType != "kubernetes" && providerType != "static" && strings.TrimSpace(spec.TargetServer) == "" {
+		errs = append(errs, field.Required(path.Child("targetServer"), "the target server is required by providers of type "+providerType))
+	}
+
+	// datadog providers only reference the secret by its name, it holds both of their keys
+	if providerType != "datadog" && spec.SecretKeyRef != (corev1.SecretKeySelector{}) && !provider.HasSecretDefined() {
+		errs = append(errs, field.Invalid(path.Child("secretKeyRef"), spec.SecretKeyRef, "both the name and the key of the secret have to be set"))
+	}
+	if provider.HasSecretDefined() && spec.BasicAuth != nil {
+		errs = append(errs, field.Forbidden(path.Child("basicAuth"), "the secretKeyRef and basicAuth cannot be used together"))
+	}
+	if spec.OAuth != nil {
+		if providerType != "dynatrace" {
+			errs = append(errs, field.Forbidden(path.Child("oauth"), "OAuth is only supported by providers of type dynatrace"))
+		}
+		if provider.HasSecretDefined() {
+			errs = append(errs, field.Forbidden(path.Child("oauth"), "the secretKeyRef and oauth cannot be used together"))
+		}
+	}
+	if spec.TLS != nil && (spec.TLS.Cert == nil) != (spec.TLS.Key == nil) {
+		errs = append(errs, field.Invalid(path.Child("tls"), "", "the client certificate and key have to be configured together"))
+	}
+	if spec.Insecure {
+		if providerType != "grpc" {
+			errs = append(errs, field.Forbidden(path.Child("insecure"), "insecure connections are only supported by providers of type grpc"))
+		}
+		if spec.TLS != nil {
+			errs = append(errs, field.Forbidden(path.Child("insecure"), "the tls and insecure properties cannot be used together"))
+		}
+		if provider.HasSecretDefined() || spec.BasicAuth != nil {
+			errs = append(errs, field.Forbidden(path.Child("insecure"), "credentials are not sent to the target server over an insecure connection"))
+		}
+	}
+
+	switch providerType {
+	case "dynatrace":
+		if !provider.HasSecretDefined() && spec.OAuth == nil {
+			errs = append(errs, field.Required(path.Child("secretKeyRef"), "the API token or OAuth client credentials are required by providers of type dynatrace"))
+		}
+	case "datadog":
+		if spec.SecretKeyRef.Name == "" {
+			errs = append(errs, field.Required(path.Child("secretKeyRef"), "the secret holding the API and application keys is required by providers of type datadog"))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,242 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	klcv1alpha2 "github.com/keptn/lifecycle-toolkit/operator/api/v1alpha2"
+	"github.com/keptn/lifecycle-toolkit/operator/controllers/common/fake"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// newAdmissionRequest returns a create request for the given object, namespaced objects are created in the default namespace
+func newAdmissionRequest(t *testing.T, obj runtime.Object) admission.Request {
+	raw, err := json.Marshal(obj)
+	require.Nil(t, err)
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	namespace := "default"
+	if strings.HasPrefix(gvk.Kind, "Cluster") {
+		namespace = ""
+	}
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+			Operation: admissionv1.Create,
+			Namespace: namespace,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func newTestDecoder(t *testing.T) *admission.Decoder {
+	// the fake client registers the types of the operator in the scheme
+	_, err := fake.NewClient()
+	require.Nil(t, err)
+	decoder, err := admission.NewDecoder(scheme.Scheme)
+	require.Nil(t, err)
+	return decoder
+}
+
+func TestEvaluationProviderValidatingWebhook_Handle(t *testing.T) {
+	secretRef := corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"},
+		Key:                  "token",
+	}
+
+	tests := []struct {
+		name    string
+		objName string
+		spec    klcv1alpha2.KeptnEvaluationProviderSpec
+		allowed bool
+		reason  string
+	}{
+		{
+			name:    "prometheus",
+			objName: "prometheus",
+			spec:    klcv1alpha2.KeptnEvaluationProviderSpec{TargetServer: "http://prometheus:9090"},
+			allowed: true,
+		},
+		{
+			name:    "kubernetes without target server",
+			objName: "cluster",
+			spec:    klcv1alpha2.KeptnEvaluationProviderSpec{Type: "kubernetes"},
+			allowed: true,
+		},
+		{
+			name:    "name is not a type",
+			objName: "prometheus-prod",
+			spec:    klcv1alpha2.KeptnEvaluationProviderSpec{TargetServer: "http://prometheus:9090"},
+			reason:  "spec.type",
+		},
+		{
+			name:    "missing target server",
+			objName: "prometheus",
+			spec:    klcv1alpha2.KeptnEvaluationProviderSpec{},
+			reason:  "spec.targetServer",
+		},
+		{
+			name:    "dynatrace without credentials",
+			objName: "dynatrace",
+			spec:    klcv1alpha2.KeptnEvaluationProviderSpec{TargetServer: "https://abc.live.dynatrace.com"},
+			reason:  "spec.secretKeyRef",
+		},
+		{
+			name:    "dynatrace with OAuth",
+			objName: "dynatrace",
+			spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+				TargetServer: "https://abc.live.dynatrace.com",
+				OAuth:        &klcv1alpha2.OAuthClientCredentials{ClientID: secretRef, ClientSecret: secretRef},
+			},
+			allowed: true,
+		},
+		{
+			name:    "OAuth with another type",
+			objName: "prometheus",
+			spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+				TargetServer: "http://prometheus:9090",
+				OAuth:        &klcv1alpha2.OAuthClientCredentials{ClientID: secretRef, ClientSecret: secretRef},
+			},
+			reason: "spec.oauth",
+		},
+		{
+			name:    "datadog without secret",
+			objName: "datadog",
+			spec:    klcv1alpha2.KeptnEvaluationProviderSpec{TargetServer: "https://api.datadoghq.eu"},
+			reason:  "spec.secretKeyRef",
+		},
+		{
+			name:    "datadog secret without key",
+			objName: "datadog",
+			spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+				TargetServer: "https://api.datadoghq.eu",
+				SecretKeyRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "datadog-keys"}},
+			},
+			allowed: true,
+		},
+		{
+			name:    "secret without key",
+			objName: "prometheus",
+			spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+				TargetServer: "http://prometheus:9090",
+				SecretKeyRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"}},
+			},
+			reason: "spec.secretKeyRef",
+		},
+		{
+			name:    "secret and basic auth",
+			objName: "prometheus",
+			spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+				TargetServer: "http://prometheus:9090",
+				SecretKeyRef: secretRef,
+				BasicAuth:    &klcv1alpha2.BasicAuth{Username: secretRef, Password: secretRef},
+			},
+			reason: "spec.basicAuth",
+		},
+		{
+			name:    "client certificate without key",
+			objName: "prometheus",
+			spec: klcv1alpha2.KeptnEvaluationProviderSpec{
+				TargetServer: "http://prometheus:9090",
+				TLS:          &klcv1alpha2.TLSConfig{Cert: &secretRef},
+			},
+			reason: "spec.tls",
+		},
+		{
+			name:    "insecure grpc",
+			objName: "in-house",
+			spec:    klcv1alpha2.KeptnEvaluationProviderSpec{Type: "grpc", TargetServer: "evaluator:50051", Insecure: true},
+			allowed: true,
+		},
+		{
+			name:    "insecure grpc with credentials",
+			objName: "in-house",
+			spec:    klcv1alpha2.KeptnEvaluationProviderSpec{Type: "grpc", TargetServer: "evaluator:50051", Insecure: true, SecretKeyRef: secretRef},
+			reason:  "spec.insecure",
+		},
+		{
+			name:    "insecure with another type",
+			objName: "prometheus",
+			spec:    klcv1alpha2.KeptnEvaluationProviderSpec{TargetServer: "http://prometheus:9090", Insecure: true},
+			reason:  "spec.insecure",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &EvaluationProviderValidatingWebhook{
+				decoder: newTestDecoder(t),
+				Log:     ctrl.Log.WithName("testytest"),
+			}
+			provider := &klcv1alpha2.KeptnEvaluationProvider{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "lifecycle.keptn.sh/v1alpha2", Kind: "KeptnEvaluationProvider"},
+				ObjectMeta: metav1.ObjectMeta{Name: tt.objName, Namespace: "default"},
+				Spec:       tt.spec,
+			}
+			resp := a.Handle(context.TODO(), newAdmissionRequest(t, provider))
+			require.Equal(t, tt.allowed, resp.Allowed)
+			if !tt.allowed {
+				require.Contains(t, string(resp.Result.Reason), tt.reason)
+			}
+		})
+	}
+}
+
+func TestEvaluationProviderValidatingWebhook_Handle_Cluster(t *testing.T) {
+	tests := []struct {
+		name              string
+		spec              klcv1alpha2.KeptnEvaluationProviderSpec
+		namespaceSelector *metav1.LabelSelector
+		allowed           bool
+		reason            string
+	}{
+		{
+			name:    "prometheus",
+			spec:    klcv1alpha2.KeptnEvaluationProviderSpec{Type: "prometheus", TargetServer: "http://prometheus:9090"},
+			allowed: true,
+		},
+		{
+			name:   "missing target server",
+			spec:   klcv1alpha2.KeptnEvaluationProviderSpec{Type: "prometheus"},
+			reason: "spec.targetServer",
+		},
+		{
+			name: "invalid namespace selector",
+			spec: klcv1alpha2.KeptnEvaluationProviderSpec{Type: "prometheus", TargetServer: "http://prometheus:9090"},
+			namespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Like"}},
+			},
+			reason: "spec.namespaceSelector",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &EvaluationProviderValidatingWebhook{
+				decoder: newTestDecoder(t),
+				Log:     ctrl.Log.WithName("testytest"),
+			}
+			provider := &klcv1alpha2.ClusterKeptnEvaluationProvider{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "lifecycle.keptn.sh/v1alpha2", Kind: "ClusterKeptnEvaluationProvider"},
+				ObjectMeta: metav1.ObjectMeta{Name: "prometheus-prod"},
+				Spec: klcv1alpha2.ClusterKeptnEvaluationProviderSpec{
+					KeptnEvaluationProviderSpec: tt.spec,
+					NamespaceSelector:           tt.namespaceSelector,
+				},
+			}
+			resp := a.Handle(context.TODO(), newAdmissionRequest(t, provider))
+			require.Equal(t, tt.allowed, resp.Allowed)
+			if !tt.allowed {
+				require.Contains(t, string(resp.Result.Reason), tt.reason)
+			}
+		})
+	}
+}
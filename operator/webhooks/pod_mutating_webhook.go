@@ -45,6 +45,8 @@ const InvalidAnnotationMessage = "Invalid annotations"
 
 var ErrTooLongAnnotations = fmt.Errorf("too long annotations, maximum length for app and workload is 25 characters, for version 12 characters")
 
+var ErrInvalidFailActionAnnotation = fmt.Errorf("invalid value of the %s annotation, use fail, warn or ignore", apicommon.EvaluationFailActionAnnotation)
+
 // Handle inspects incoming Pods and injects the Keptn scheduler if they contain the Keptn lifecycle annotations.
 func (a *PodMutatingWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
 
@@ -151,6 +153,10 @@ func (a *PodMutatingWebhook) isPodAnnotated(pod *corev1.Pod) (bool, error) {
 	}
 
 	if gotWorkloadAnnotation {
+		evaluationFailAction, _ := getLabelOrAnnotation(&pod.ObjectMeta, apicommon.EvaluationFailActionAnnotation, "")
+		if err := validateEvaluationFailAction(evaluationFailAction); err != nil {
+			return false, err
+		}
 		if !gotVersionAnnotation {
 			if len(pod.Annotations) == 0 {
 				pod.Annotations = make(map[string]string)
@@ -206,7 +212,7 @@ func (a *PodMutatingWebhook) fetchParentObjectAndCopyLabels(ctx context.Context,
 }
 
 func (a *PodMutatingWebhook) copyResourceLabelsIfPresent(sourceResource *metav1.ObjectMeta, targetPod *corev1.Pod) (bool, error) {
-	var workloadName, appName, version, preDeploymentChecks, postDeploymentChecks, preEvaluationChecks, postEvaluationChecks string
+	var workloadName, appName, version, preDeploymentChecks, postDeploymentChecks, preEvaluationChecks, postEvaluationChecks, evaluationFailAction string
 	var gotWorkloadName, gotVersion bool
 
 	workloadName, gotWorkloadName = getLabelOrAnnotation(sourceResource, apicommon.WorkloadAnnotation, apicommon.K8sRecommendedWorkloadAnnotations)
@@ -216,6 +222,7 @@ func (a *PodMutatingWebhook) copyResourceLabelsIfPresent(sourceResource *metav1.
 	postDeploymentChecks, _ = getLabelOrAnnotation(sourceResource, apicommon.PostDeploymentTaskAnnotation, "")
 	preEvaluationChecks, _ = getLabelOrAnnotation(sourceResource, apicommon.PreDeploymentEvaluationAnnotation, "")
 	postEvaluationChecks, _ = getLabelOrAnnotation(sourceResource, apicommon.PostDeploymentEvaluationAnnotation, "")
+	evaluationFailAction, _ = getLabelOrAnnotation(sourceResource, apicommon.EvaluationFailActionAnnotation, "")
 
 	if len(workloadName) > apicommon.MaxWorkloadNameLength || len(version) > apicommon.MaxVersionLength {
 		return false, ErrTooLongAnnotations
@@ -226,6 +233,9 @@ func (a *PodMutatingWebhook) copyResourceLabelsIfPresent(sourceResource *metav1.
 	}
 
 	if gotWorkloadName {
+		if err := validateEvaluationFailAction(evaluationFailAction); err != nil {
+			return false, err
+		}
 		setMapKey(targetPod.Annotations, apicommon.WorkloadAnnotation, workloadName)
 
 		if !gotVersion {
@@ -239,12 +249,21 @@ func (a *PodMutatingWebhook) copyResourceLabelsIfPresent(sourceResource *metav1.
 		setMapKey(targetPod.Annotations, apicommon.PostDeploymentTaskAnnotation, postDeploymentChecks)
 		setMapKey(targetPod.Annotations, apicommon.PreDeploymentEvaluationAnnotation, preEvaluationChecks)
 		setMapKey(targetPod.Annotations, apicommon.PostDeploymentEvaluationAnnotation, postEvaluationChecks)
+		setMapKey(targetPod.Annotations, apicommon.EvaluationFailActionAnnotation, evaluationFailAction)
 
 		return true, nil
 	}
 	return false, nil
 }
 
+// validateEvaluationFailAction rejects fail actions that the evaluations of the workload would not understand
+func validateEvaluationFailAction(value string) error {
+	if !klcv1alpha2.FailAction(value).IsValid() {
+		return fmt.Errorf("%w, got %q", ErrInvalidFailActionAnnotation, value)
+	}
+	return nil
+}
+
 func (a *PodMutatingWebhook) isAppAnnotationPresent(pod *corev1.Pod) (bool, error) {
 	app, gotAppAnnotation := getLabelOrAnnotation(&pod.ObjectMeta, apicommon.AppAnnotation, apicommon.K8sRecommendedAppAnnotations)
 
@@ -417,6 +436,8 @@ func (a *PodMutatingWebhook) generateWorkload(ctx context.Context, pod *corev1.P
 		postDeploymentEvaluation = strings.Split(annotations, ",")
 	}
 
+	evaluationFailAction, _ := getLabelOrAnnotation(&pod.ObjectMeta, apicommon.EvaluationFailActionAnnotation, "")
+
 	// create TraceContext
 	// follow up with a Keptn propagator that JSON-encoded the OTel map into our own key
 	traceContextCarrier := propagation.MapCarrier{}
@@ -438,6 +459,7 @@ func (a *PodMutatingWebhook) generateWorkload(ctx context.Context, pod *corev1.P
 			PostDeploymentTasks:       postDeploymentTasks,
 			PreDeploymentEvaluations:  preDeploymentEvaluation,
 			PostDeploymentEvaluations: postDeploymentEvaluation,
+			EvaluationFailAction:      klcv1alpha2.FailAction(evaluationFailAction),
 		},
 	}
 }
@@ -394,6 +394,22 @@ func TestPodMutatingWebhook_isPodAnnotated(t *testing.T) {
 			want:    false,
 			wantErr: true,
 		},
+		{
+			name: "Test error when evaluation fail action is invalid",
+			args: args{
+				pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							apicommon.WorkloadAnnotation:             "some-workload-name",
+							apicommon.VersionAnnotation:              "v1.0.0",
+							apicommon.EvaluationFailActionAnnotation: "warning",
+						},
+					},
+				},
+			},
+			want:    false,
+			wantErr: true,
+		},
 		{
 			name: "Test return true when pod has workload annotation",
 			args: args{
@@ -771,6 +787,7 @@ func TestPodMutatingWebhook_copyResourceLabelsIfPresent(t *testing.T) {
 						apicommon.PostDeploymentTaskAnnotation:       "some-post-deployment-task",
 						apicommon.PreDeploymentEvaluationAnnotation:  "some-pre-deployment-evaluation",
 						apicommon.PostDeploymentEvaluationAnnotation: "some-post-deployment-evaluation",
+						apicommon.EvaluationFailActionAnnotation:     "warn",
 					},
 				},
 				targetPod: &corev1.Pod{
@@ -793,6 +810,7 @@ func TestPodMutatingWebhook_copyResourceLabelsIfPresent(t *testing.T) {
 						apicommon.PostDeploymentTaskAnnotation:       "some-post-deployment-task",
 						apicommon.PreDeploymentEvaluationAnnotation:  "some-pre-deployment-evaluation",
 						apicommon.PostDeploymentEvaluationAnnotation: "some-post-deployment-evaluation",
+						apicommon.EvaluationFailActionAnnotation:     "warn",
 					},
 				},
 			},
@@ -897,6 +915,22 @@ func TestPodMutatingWebhook_copyResourceLabelsIfPresent(t *testing.T) {
 			want:    false,
 			wantErr: true,
 		},
+		{
+			name: "Test that error is returned with invalid evaluation fail action",
+			args: args{
+				sourceResource: &metav1.ObjectMeta{
+					Name: "testSourceObject",
+					Annotations: map[string]string{
+						apicommon.WorkloadAnnotation:             "some-workload-name",
+						apicommon.VersionAnnotation:              "v1.0.0",
+						apicommon.EvaluationFailActionAnnotation: "warning",
+					},
+				},
+				targetPod: &corev1.Pod{},
+			},
+			want:    false,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {